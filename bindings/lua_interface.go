@@ -1,35 +1,35 @@
 package bindings
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os/exec"
 	"path/filepath"
-	"strings"
+	"sort"
 	"time"
+
+	lua "github.com/yuin/gopher-lua"
 )
 
 // lua接口封装
+//
+// 规则以内嵌的 gopher-lua 运行时执行，不再为每次过滤 fork 一个
+// `lua` 子进程：FileMetadata 以 Lua userdata 形式直接传入规则脚本，
+// 省去了此前 JSON 序列化/反序列化的往返开销。
 type LuaInterface struct {
-	luaPath    string
 	filterPath string
 	verbose    bool
+	pool       *lStatePool
 }
 
 // 创建lua接口
 func NewLuaInterface(verbose bool) *LuaInterface {
 	return &LuaInterface{
-		luaPath:    "lua",
 		filterPath: filepath.Join("lua", "filter.lua"),
 		verbose:    verbose,
+		pool:       newLStatePool(),
 	}
 }
 
-// 解释器路径
-func (l *LuaInterface) SetLuaPath(path string) {
-	l.luaPath = path
-}
-
 // 过滤器路径
 func (l *LuaInterface) SetFilterPath(path string) {
 	l.filterPath = path
@@ -53,60 +53,116 @@ type FilterStats struct {
 
 // 调用lua过滤文件
 func (l *LuaInterface) FilterFiles(ruleFile string, files []FileMetadata) (*FilterResult, error) {
-	filesJSON, err := json.Marshal(files)
-	if err != nil {
-		return nil, fmt.Errorf("Errorf: %V", err)
+	return l.FilterFilesWithContext(context.Background(), ruleFile, files)
+}
+
+// FilterFilesWithContext 与 FilterFiles 相同，但允许调用方通过 ctx 取消
+// 正在执行的规则脚本（经 LState.SetContext 生效），而不是等待其跑完。
+func (l *LuaInterface) FilterFilesWithContext(ctx context.Context, ruleFile string, files []FileMetadata) (*FilterResult, error) {
+	L := l.pool.Get(ruleFile)
+	defer l.pool.Put(ruleFile, L)
+
+	L.SetContext(ctx)
+	registerFileMetadataType(L)
+
+	if err := L.DoFile(ruleFile); err != nil {
+		return nil, fmt.Errorf("加载Lua规则文件失败: %v", err)
 	}
 
-	args := []string{l.filterPath, ruleFile, string(filesJSON)}
-	cmd := exec.Command(l.luaPath, args...)
+	shouldSyncFn := L.GetGlobal("should_sync")
+	if shouldSyncFn == lua.LNil {
+		return nil, fmt.Errorf("规则文件未定义 should_sync 函数: %s", ruleFile)
+	}
+	getPriorityFn := L.GetGlobal("get_priority")
 
-	output, err := cmd.Output()
-	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("Lua error: %v , Errorf: %s", err, string(exitError.Stderr))
+	filtered := make([]FileMetadata, 0, len(files))
+	for _, f := range files {
+		keep, err := callShouldSync(L, shouldSyncFn, f)
+		if err != nil {
+			return nil, fmt.Errorf("执行 should_sync 失败 (%s): %v", f.Path, err)
+		}
+		if keep {
+			filtered = append(filtered, f)
 		}
-		return nil, fmt.Errorf("Errorf: %v", err)
 	}
 
-	var result FilterResult
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("Lua error: %v , Errorf: %s", err, string(output))
+	if getPriorityFn != lua.LNil {
+		if err := sortByPriority(L, getPriorityFn, filtered); err != nil {
+			return nil, fmt.Errorf("执行 get_priority 失败: %v", err)
+		}
 	}
 
-	if !result.Status {
-		return nil, fmt.Errorf("Errorf: %s", result.Message)
+	stats := &FilterStats{
+		TotalFiles:    len(files),
+		FilteredFiles: len(filtered),
+		ExcludedFiles: len(files) - len(filtered),
+	}
+	if stats.TotalFiles > 0 {
+		stats.ExclusionRate = float64(stats.ExcludedFiles) / float64(stats.TotalFiles)
 	}
-	return &result, nil
+
+	return &FilterResult{
+		FilteredFiles: filtered,
+		Status:        true,
+		Statistics:    stats,
+	}, nil
+}
+
+// callShouldSync 以 FileMetadata 的 userdata 形式调用规则脚本中的
+// should_sync(file) 函数。
+func callShouldSync(L *lua.LState, fn lua.LValue, f FileMetadata) (bool, error) {
+	ud := newFileMetadataUserData(L, f)
+	if err := L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, ud); err != nil {
+		return false, err
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+	return lua.LVAsBool(ret), nil
+}
+
+// callGetPriority 以 FileMetadata 的 userdata 形式调用规则脚本中的
+// get_priority(file) 函数。
+func callGetPriority(L *lua.LState, fn lua.LValue, f FileMetadata) (float64, error) {
+	ud := newFileMetadataUserData(L, f)
+	if err := L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, ud); err != nil {
+		return 0, err
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+	return float64(lua.LVAsNumber(ret)), nil
+}
+
+// sortByPriority 按规则脚本中 get_priority 返回的优先级对 files 原地降序排序。
+func sortByPriority(L *lua.LState, getPriorityFn lua.LValue, files []FileMetadata) error {
+	priorities := make([]float64, len(files))
+	for i, f := range files {
+		p, err := callGetPriority(L, getPriorityFn, f)
+		if err != nil {
+			return err
+		}
+		priorities[i] = p
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return priorities[i] > priorities[j]
+	})
+	return nil
 }
 
 // 验证规则文件
 func (l *LuaInterface) ValidateRuleFile(ruleFile string) error {
 	if _, err := filepath.Abs(ruleFile); err != nil {
-		return fmt.Errorf("Errorf: %v", err)
+		return fmt.Errorf("解析规则文件路径失败: %v", err)
 	}
-	script := fmt.Sprintf(`
--- 语法检查脚本
-local success, err = pcall(function()
-    dofile("%s")
-end)
-
-if success then
-    print("OK")
-else
-    print("ERROR: " .. tostring(err))
-end
-`, ruleFile)
 
-	cmd := exec.Command(l.luaPath, "-e", script)
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("Errorf: %v", err)
-	}
+	L := l.pool.Get(ruleFile)
+	defer l.pool.Put(ruleFile, L)
 
-	outputStr := strings.TrimSpace(string(output))
-	if outputStr != "OK" {
-		return fmt.Errorf("Errorf: %s", outputStr)
+	if err := L.DoFile(ruleFile); err != nil {
+		return fmt.Errorf("规则文件存在语法或运行时错误: %v", err)
+	}
+	if L.GetGlobal("should_sync") == lua.LNil {
+		return fmt.Errorf("规则文件未定义 should_sync 函数: %s", ruleFile)
 	}
 	return nil
 }
@@ -116,29 +172,29 @@ func (l *LuaInterface) TestRuleFile(ruleFile string) (*RuleTestResult, error) {
 		{
 			Path:          "/test/file1.txt",
 			Hash:          "hash1",
-			Size:          1024,
+			Size:          "1024",
 			ModifiledTime: "2023-01-01T00:00:00Z",
 			Permissions:   "0644",
 		},
 		{
-			Path:         "/test/.hidden",
-			Hash:         "hash2",
-			Size:         512,
-			ModifiedTime: "2023-01-01T00:00:00Z",
-			Permissions:  "0644",
+			Path:          "/test/.hidden",
+			Hash:          "hash2",
+			Size:          "512",
+			ModifiledTime: "2023-01-01T00:00:00Z",
+			Permissions:   "0644",
 		},
 		{
-			Path:         "/test/node_modules/package.json",
-			Hash:         "hash3",
-			Size:         2048,
-			ModifiedTime: "2023-01-01T00:00:00Z",
-			Permissions:  "0644",
+			Path:          "/test/node_modules/package.json",
+			Hash:          "hash3",
+			Size:          "2048",
+			ModifiledTime: "2023-01-01T00:00:00Z",
+			Permissions:   "0644",
 		},
 	}
 
 	result, err := l.FilterFiles(ruleFile, testFiles)
 	if err != nil {
-		return nil, fmt.Errorf("Errorf: %v".err)
+		return nil, fmt.Errorf("测试规则文件失败: %v", err)
 	}
 
 	return &RuleTestResult{
@@ -159,62 +215,17 @@ type RuleTestResult struct {
 	Statistics    *FilterStats   `json:"statistics,omitempty"`
 }
 
-// 获取lua版本信息
+// 获取lua运行时版本信息
 func (l *LuaInterface) GetLuaVersion() (string, error) {
-	cmd := exec.Command(l.luaPath, "-v")
-	output, err := cmd.Output()
-	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			return strings.TrimSpace(string(exitError.Stderr)), nil
-		}
-		return "", fmt.Errorf("Errorf: %v", err)
-	}
-	return strings.TrimSpace(string(output)), nil
+	return fmt.Sprintf("gopher-lua (embedded, %s 兼容)", lua.LuaVersion), nil
 }
 
 // 检查 Lua 依赖
+//
+// 内嵌的 gopher-lua 运行时不依赖外部 cjson/dkjson 等 JSON 库 ——
+// FileMetadata 直接以 userdata 形式传给规则脚本，无需 JSON 编解码，
+// 因此这里不再有依赖可检查。
 func (l *LuaInterface) CheckLuaDependencies() error {
-	script := `
--- 检查基本功能
-local json_available = false
-local success, json = pcall(require, "json")
-if success then
-    json_available = true
-else
-    success, json = pcall(require, "cjson")
-    if success then
-        json_available = true
-    else
-        success, json = pcall(require, "dkjson")
-        if success then
-            json_available = true
-        end
-    end
-end
-
-if json_available then
-    print("JSON_OK")
-else
-    print("JSON_MISSING")
-end
-
--- 检查其他基本功能
-print("BASIC_OK")
-`
-
-	cmd := exec.Command(l.luaPath, "-e", script)
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("Errorf: %v", err)
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "JSON_MISSING" {
-			return fmt.Errorf("缺少 Lua JSON 库 (需要 json, cjson 或 dkjson 中的一个)")
-		}
-	}
-
 	return nil
 }
 
@@ -222,42 +233,43 @@ print("BASIC_OK")
 func (l *LuaInterface) CreateDefaultRuleFile(outputPath string) error {
 	defaultRules := `-- 默认 FileSync CLI 规则文件
 -- 此文件定义了文件同步的过滤规则
+-- file 是一个 FileMetadata userdata，可通过 file.path / file.hash /
+-- file.size / file.modified_time / file.permissions 访问其字段
 
 -- 判断文件是否应该同步
-function should_sync(file_path)
-    -- 忽略隐藏文件
-    local filename = file_path:match("([^/\\]+)$")
+function should_sync(file)
+    local filename = file.path:match("([^/\\]+)$")
     if filename and filename:sub(1, 1) == "." then
         return false
     end
-    
+
     -- 忽略常见的临时和构建目录
     local ignore_patterns = {
         ".git", ".svn", "node_modules", "__pycache__",
         ".DS_Store", "Thumbs.db", "*.tmp", "*.log"
     }
-    
+
     for _, pattern in ipairs(ignore_patterns) do
-        if file_path:find(pattern, 1, true) then
+        if file.path:find(pattern, 1, true) then
             return false
         end
     end
-    
+
     return true
 end
 
 -- 获取文件同步优先级
-function get_priority(file_path)
+function get_priority(file)
     -- 配置文件高优先级
-    if file_path:match("%.json$") or file_path:match("%.yaml$") or file_path:match("%.yml$") then
+    if file.path:match("%.json$") or file.path:match("%.yaml$") or file.path:match("%.yml$") then
         return 10
     end
-    
+
     -- 源代码文件中等优先级
-    if file_path:match("%.go$") or file_path:match("%.py$") or file_path:match("%.rs$") then
+    if file.path:match("%.go$") or file.path:match("%.py$") or file.path:match("%.rs$") then
         return 5
     end
-    
+
     -- 默认优先级
     return 1
 end
@@ -286,33 +298,19 @@ func (m *LuaManager) GetInterface() *LuaInterface {
 }
 
 // 超时文件过滤
+//
+// 通过 context.WithTimeout + LState.SetContext 让超时真正中止 Lua 脚本
+// 的执行，而不是任由一个已无人等待结果的 goroutine 继续跑到底。
 func (m *LuaManager) FilterFilesWithTimeout(ruleFile string, files []FileMetadata) (*FilterResult, error) {
-	done := make(chan struct{})
-	var result *FilterResult
-	var err error
-	go func() {
-		defer close(done)
-		result, err = m.interface_.FilterFiles(ruleFile, files)
-	}()
-
-	select {
-	case <-done:
-		return result, err
-	case <-time.After(m.timeout):
-		return nil, fmt.Errorf("Timeout: %v", m.timeout)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	return m.interface_.FilterFilesWithContext(ctx, ruleFile, files)
 }
 
 // lua接口健康检查
 func (l *LuaInterface) HealthCheck() error {
-	if _, err := l.GetLuaVersion(); err != nil {
-		return fmt.Errorf("Errorf: %v", err)
-	}
-	if err := l.CheckLuaDependencies(); err != nil {
-		return fmt.Errorf("Errorf: %v", err)
-	}
 	if _, err := filepath.Abs(l.filterPath); err != nil {
-		return fmt.Errorf("Errorf: %v", err)
+		return fmt.Errorf("过滤器路径无效: %v", err)
 	}
 	return nil
 }
@@ -0,0 +1,108 @@
+package bindings
+
+import (
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// lStatePool 是一个可复用的 *lua.LState 池，避免为每次规则过滤都创建
+// 并初始化一个新的 Lua 虚拟机。沿用 gopher-lua 官方推荐的池化模式，
+// 但按 ruleFile 分桶：gopher-lua 没有"清空 _G"这样的 API，同一个
+// LState 执行完一个规则文件后，它定义的全局函数/变量会一直留在
+// _G 里，若后续被另一个规则文件复用就会看到上一个脚本残留的全局
+// 状态。按 ruleFile 分桶后，每个桶里的 LState 只会反复 DoFile 同一个
+// 脚本（幂等，全局定义被同名覆盖），不同规则文件之间不再共享、也就
+// 不会互相污染。
+type lStatePool struct {
+	mu   sync.Mutex
+	free map[string][]*lua.LState
+}
+
+// newLStatePool 创建一个空池，LState 按需惰性创建。
+func newLStatePool() *lStatePool {
+	return &lStatePool{free: make(map[string][]*lua.LState)}
+}
+
+// Get 从 ruleFile 对应的桶中取出一个空闲的 LState，桶为空时创建一个新的。
+func (p *lStatePool) Get(ruleFile string) *lua.LState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bucket := p.free[ruleFile]
+	n := len(bucket)
+	if n == 0 {
+		return lua.NewState()
+	}
+
+	L := bucket[n-1]
+	p.free[ruleFile] = bucket[:n-1]
+	return L
+}
+
+// Put 将用完的 LState 放回 ruleFile 对应的桶中以便复用。
+func (p *lStatePool) Put(ruleFile string, L *lua.LState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.free[ruleFile] = append(p.free[ruleFile], L)
+}
+
+// Shutdown 关闭池中所有空闲的 LState，释放其底层资源。
+func (p *lStatePool) Shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for ruleFile, bucket := range p.free {
+		for _, L := range bucket {
+			L.Close()
+		}
+		delete(p.free, ruleFile)
+	}
+}
+
+// fileMetadataTypeName 是 FileMetadata 在 Lua 侧注册的 userdata 类型名。
+const fileMetadataTypeName = "FileMetadata"
+
+// registerFileMetadataType 在给定的 LState 上注册 FileMetadata 的元表，
+// 使 Lua 规则脚本可以通过 file.path / file.hash / file.size /
+// file.modified_time / file.permissions 读取字段。重复注册是安全的。
+func registerFileMetadataType(L *lua.LState) {
+	mt := L.NewTypeMetatable(fileMetadataTypeName)
+	L.SetField(mt, "__index", L.NewFunction(fileMetadataIndex))
+}
+
+// fileMetadataIndex 实现 FileMetadata userdata 的 __index 元方法。
+func fileMetadataIndex(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	field := L.CheckString(2)
+
+	fm, ok := ud.Value.(FileMetadata)
+	if !ok {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	switch field {
+	case "path":
+		L.Push(lua.LString(fm.Path))
+	case "hash":
+		L.Push(lua.LString(fm.Hash))
+	case "size":
+		L.Push(lua.LString(fm.Size))
+	case "modified_time":
+		L.Push(lua.LString(fm.ModifiledTime))
+	case "permissions":
+		L.Push(lua.LString(fm.Permissions))
+	default:
+		L.Push(lua.LNil)
+	}
+	return 1
+}
+
+// newFileMetadataUserData 将一个 FileMetadata 包装为 Lua userdata。
+func newFileMetadataUserData(L *lua.LState, fm FileMetadata) *lua.LUserData {
+	ud := L.NewUserData()
+	ud.Value = fm
+	ud.Metatable = L.GetTypeMetatable(fileMetadataTypeName)
+	return ud
+}
@@ -0,0 +1,73 @@
+package bindings
+
+import (
+	"sync"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// TestLStatePoolPartitionsByRuleFile 验证放回某个 ruleFile 桶的 LState
+// 不会被另一个 ruleFile 的 Get 取走 —— 这正是按 ruleFile 分桶要解决的
+// 全局状态污染问题：不同规则脚本绝不能复用同一个已执行过的 LState。
+func TestLStatePoolPartitionsByRuleFile(t *testing.T) {
+	p := newLStatePool()
+
+	a := p.Get("a.lua")
+	b := p.Get("b.lua")
+	if a == b {
+		t.Fatal("不同ruleFile在池为空时应当各自拿到新建的LState")
+	}
+
+	p.Put("a.lua", a)
+	p.Put("b.lua", b)
+
+	if got := p.Get("a.lua"); got != a {
+		t.Fatal("Get(\"a.lua\")应当取回之前为a.lua放回的同一个LState")
+	}
+	if got := p.Get("b.lua"); got != b {
+		t.Fatal("Get(\"b.lua\")应当取回之前为b.lua放回的同一个LState")
+	}
+}
+
+func TestLStatePoolGetOnEmptyBucketCreatesNewState(t *testing.T) {
+	p := newLStatePool()
+
+	L := p.Get("fresh.lua")
+	if L == nil {
+		t.Fatal("空桶上的Get应当返回一个新建的LState而不是nil")
+	}
+}
+
+// TestLStatePoolConcurrentAccess 在 -race 下验证并发 Get/Put 不会相互踩踏。
+func TestLStatePoolConcurrentAccess(t *testing.T) {
+	p := newLStatePool()
+	ruleFiles := []string{"x.lua", "y.lua", "z.lua"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		ruleFile := ruleFiles[i%len(ruleFiles)]
+		wg.Add(1)
+		go func(ruleFile string) {
+			defer wg.Done()
+			L := p.Get(ruleFile)
+			p.Put(ruleFile, L)
+		}(ruleFile)
+	}
+	wg.Wait()
+}
+
+func TestLStatePoolShutdownClosesAllStates(t *testing.T) {
+	p := newLStatePool()
+
+	p.Put("a.lua", lua.NewState())
+	p.Put("b.lua", lua.NewState())
+
+	p.Shutdown()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.free) != 0 {
+		t.Fatalf("Shutdown后池应当清空所有桶，got %d个桶", len(p.free))
+	}
+}
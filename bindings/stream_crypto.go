@@ -0,0 +1,270 @@
+package bindings
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 流式 AEAD 加密格式：
+//
+//	[magic(8)][algo(1)][salt(16)][baseNonce(12)]  -- 文件头，仅一次
+//	[frameLen(4)][ciphertext...]                  -- 逐帧重复至最后一帧
+//
+// 明文按 streamFrameSize 分帧，每帧使用 baseNonce 与小端序帧计数器
+// 派生出的独立 96 位 nonce 加密，任何一帧被截断或篡改都会在该帧的
+// AEAD 校验上被发现，而不必等到整个文件读完。
+//
+// frameLen 的最高位（streamFinalFrameBit）是"末帧"标记：只有置位了该
+// 标记的帧之后才允许遇到 EOF；如果流恰好在某个普通帧的边界处被截断，
+// 读端会在尝试读取下一帧头时遇到 EOF 而不是看到末帧标记，从而报错而
+// 不是把被截断的流当成合法的空结尾静默放行。
+const (
+	streamFrameSize     = 1 * 1024 * 1024 // 每帧明文大小 1 MiB
+	streamMagic         = "SYNCCLI1"
+	streamAlgoAESGCM    = byte(1)
+	streamNonceSize     = 12
+	streamSaltSize      = 16
+	streamKeySize       = 32 // AES-256
+	streamFinalFrameBit = uint32(1) << 31
+	streamMaxFrameLen   = streamFinalFrameBit - 1
+)
+
+// KDFParams 是由口令派生文件密钥所用的 Argon2id 参数，可通过配置暴露给用户。
+type KDFParams struct {
+	Time    uint32 // 迭代次数
+	Memory  uint32 // 内存开销，单位 KiB
+	Threads uint8  // 并行度
+}
+
+// DefaultKDFParams 返回一组适中的 Argon2id 默认参数。
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+// deriveFileKey 使用 Argon2id 从口令和盐值派生出 32 字节的 AES-256 密钥。
+func deriveFileKey(passphrase, salt []byte, params KDFParams) []byte {
+	return argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, streamKeySize)
+}
+
+// streamHeader 是流加密文件开头的头部：算法 id、KDF 盐值、基础 nonce。
+type streamHeader struct {
+	Algo      byte
+	Salt      [streamSaltSize]byte
+	BaseNonce [streamNonceSize]byte
+}
+
+func writeStreamHeader(dst io.Writer, h streamHeader) error {
+	if _, err := io.WriteString(dst, streamMagic); err != nil {
+		return err
+	}
+	if _, err := dst.Write([]byte{h.Algo}); err != nil {
+		return err
+	}
+	if _, err := dst.Write(h.Salt[:]); err != nil {
+		return err
+	}
+	if _, err := dst.Write(h.BaseNonce[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readStreamHeader(src io.Reader) (streamHeader, error) {
+	var h streamHeader
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := io.ReadFull(src, magic); err != nil {
+		return h, fmt.Errorf("读取流加密文件头失败: %v", err)
+	}
+	if string(magic) != streamMagic {
+		return h, errors.New("不是有效的流加密文件（魔数不匹配）")
+	}
+
+	algo := make([]byte, 1)
+	if _, err := io.ReadFull(src, algo); err != nil {
+		return h, fmt.Errorf("读取算法标识失败: %v", err)
+	}
+	h.Algo = algo[0]
+
+	if _, err := io.ReadFull(src, h.Salt[:]); err != nil {
+		return h, fmt.Errorf("读取KDF盐值失败: %v", err)
+	}
+	if _, err := io.ReadFull(src, h.BaseNonce[:]); err != nil {
+		return h, fmt.Errorf("读取基础nonce失败: %v", err)
+	}
+
+	return h, nil
+}
+
+// frameNonce 用基础 nonce 与小端序帧计数器派生出第 counter 帧专属的
+// 96 位 nonce：在基础 nonce 的低 32 位上异或帧计数器。
+func frameNonce(base [streamNonceSize]byte, counter uint32) []byte {
+	nonce := make([]byte, streamNonceSize)
+	copy(nonce, base[:])
+
+	var counterBytes [4]byte
+	binary.LittleEndian.PutUint32(counterBytes[:], counter)
+	for i := 0; i < 4; i++ {
+		nonce[streamNonceSize-4+i] ^= counterBytes[i]
+	}
+	return nonce
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建AES密码失败: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建GCM失败: %v", err)
+	}
+	return gcm, nil
+}
+
+// EncryptStream 以常量内存占用对 src 做流式 AEAD 加密并写入 dst：
+// 内部按 streamFrameSize 分帧，用 Argon2id 从 passphrase 派生出的密钥
+// 以 AES-256-GCM 逐帧加密，每帧携带独立 nonce 与认证标签。取代此前
+// 一次性把整份密文读入内存的 RustFFI.EncryptFile。
+func EncryptStream(src io.Reader, dst io.Writer, passphrase []byte, params KDFParams) error {
+	salt := make([]byte, streamSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("生成KDF盐值失败: %v", err)
+	}
+
+	var baseNonce [streamNonceSize]byte
+	if _, err := rand.Read(baseNonce[:]); err != nil {
+		return fmt.Errorf("生成基础nonce失败: %v", err)
+	}
+
+	var saltArr [streamSaltSize]byte
+	copy(saltArr[:], salt)
+
+	key := deriveFileKey(passphrase, salt, params)
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return err
+	}
+
+	if err := writeStreamHeader(dst, streamHeader{Algo: streamAlgoAESGCM, Salt: saltArr, BaseNonce: baseNonce}); err != nil {
+		return fmt.Errorf("写入流加密文件头失败: %v", err)
+	}
+
+	buf := make([]byte, streamFrameSize)
+	var carry []byte
+	var counter uint32
+
+	for {
+		n := copy(buf, carry)
+		carry = nil
+
+		readN, readErr := io.ReadFull(src, buf[n:])
+		n += readN
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return fmt.Errorf("读取明文失败: %v", readErr)
+		}
+
+		final := n < len(buf)
+		if !final {
+			// buf 恰好被填满，用再读 1 个字节的方式探测后面是否还有数据，
+			// 从而区分"这恰好是最后一帧"与"还有更多数据"。
+			var peek [1]byte
+			pn, peekErr := io.ReadFull(src, peek[:])
+			if peekErr != nil && peekErr != io.EOF && peekErr != io.ErrUnexpectedEOF {
+				return fmt.Errorf("读取明文失败: %v", peekErr)
+			}
+			if pn == 0 {
+				final = true
+			} else {
+				carry = []byte{peek[0]}
+			}
+		}
+
+		nonce := frameNonce(baseNonce, counter)
+		ciphertext := gcm.Seal(nil, nonce, buf[:n], nil)
+		if uint32(len(ciphertext)) > streamMaxFrameLen {
+			return fmt.Errorf("帧过大，超出 streamMaxFrameLen: %d", len(ciphertext))
+		}
+
+		frameLenValue := uint32(len(ciphertext))
+		if final {
+			frameLenValue |= streamFinalFrameBit
+		}
+
+		var frameLen [4]byte
+		binary.LittleEndian.PutUint32(frameLen[:], frameLenValue)
+		if _, err := dst.Write(frameLen[:]); err != nil {
+			return fmt.Errorf("写入帧长度失败: %v", err)
+		}
+		if _, err := dst.Write(ciphertext); err != nil {
+			return fmt.Errorf("写入加密帧失败: %v", err)
+		}
+		counter++
+
+		if final {
+			break
+		}
+	}
+
+	return nil
+}
+
+// DecryptStream 是 EncryptStream 的逆过程：逐帧读取、解密并校验认证
+// 标签后写入 dst。任意一帧被截断或篡改都会立即返回错误，而不会把
+// 损坏的明文静默写出。
+func DecryptStream(src io.Reader, dst io.Writer, passphrase []byte, params KDFParams) error {
+	header, err := readStreamHeader(src)
+	if err != nil {
+		return err
+	}
+	if header.Algo != streamAlgoAESGCM {
+		return fmt.Errorf("不支持的加密算法标识: %d", header.Algo)
+	}
+
+	key := deriveFileKey(passphrase, header.Salt[:], params)
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return err
+	}
+
+	var counter uint32
+	for {
+		var frameLenBytes [4]byte
+		if _, err := io.ReadFull(src, frameLenBytes[:]); err != nil {
+			return fmt.Errorf("流在第 %d 帧处被截断（未读到末帧标记）: %v", counter, err)
+		}
+
+		frameLenValue := binary.LittleEndian.Uint32(frameLenBytes[:])
+		final := frameLenValue&streamFinalFrameBit != 0
+		length := frameLenValue &^ streamFinalFrameBit
+
+		ciphertext := make([]byte, length)
+		if _, err := io.ReadFull(src, ciphertext); err != nil {
+			return fmt.Errorf("读取加密帧失败: %v", err)
+		}
+
+		nonce := frameNonce(header.BaseNonce, counter)
+		plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return fmt.Errorf("第 %d 帧解密失败（可能已损坏或被篡改）: %v", counter, err)
+		}
+
+		if _, err := dst.Write(plaintext); err != nil {
+			return fmt.Errorf("写入明文失败: %v", err)
+		}
+		counter++
+
+		if final {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package bindings
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// testKDFParams 使用远小于 DefaultKDFParams 的 Argon2id 参数，避免测试
+// 因真实的派生开销而变慢。
+func testKDFParams() KDFParams {
+	return KDFParams{Time: 1, Memory: 8 * 1024, Threads: 1}
+}
+
+func TestEncryptDecryptStreamRoundTrip(t *testing.T) {
+	cases := map[string][]byte{
+		"empty":                  {},
+		"smaller than one frame": []byte("hello, synccli"),
+		"exactly one frame":      bytes.Repeat([]byte("a"), streamFrameSize),
+		"spans multiple frames":  bytes.Repeat([]byte("synccli"), streamFrameSize),
+	}
+
+	for name, plaintext := range cases {
+		t.Run(name, func(t *testing.T) {
+			passphrase := []byte("correct horse battery staple")
+
+			var encrypted bytes.Buffer
+			if err := EncryptStream(bytes.NewReader(plaintext), &encrypted, passphrase, testKDFParams()); err != nil {
+				t.Fatalf("EncryptStream失败: %v", err)
+			}
+
+			var decrypted bytes.Buffer
+			if err := DecryptStream(&encrypted, &decrypted, passphrase, testKDFParams()); err != nil {
+				t.Fatalf("DecryptStream失败: %v", err)
+			}
+
+			if !bytes.Equal(decrypted.Bytes(), plaintext) {
+				t.Fatalf("往返后的明文不一致: got %d字节, want %d字节", decrypted.Len(), len(plaintext))
+			}
+		})
+	}
+}
+
+func TestDecryptStreamWrongPassphrase(t *testing.T) {
+	plaintext := []byte("sensitive payload")
+
+	var encrypted bytes.Buffer
+	if err := EncryptStream(bytes.NewReader(plaintext), &encrypted, []byte("correct"), testKDFParams()); err != nil {
+		t.Fatalf("EncryptStream失败: %v", err)
+	}
+
+	var decrypted bytes.Buffer
+	err := DecryptStream(&encrypted, &decrypted, []byte("wrong"), testKDFParams())
+	if err == nil {
+		t.Fatal("用错误口令解密应当失败，但返回了nil")
+	}
+}
+
+// TestDecryptStreamRejectsTruncation 验证流在帧边界处被截断（例如传输中途
+// 被切断）时不会被静默当成合法的空结尾放行 —— 必须读到末帧标记才算正常
+// 结束。
+func TestDecryptStreamRejectsTruncation(t *testing.T) {
+	plaintext := bytes.Repeat([]byte("a"), streamFrameSize)
+
+	var encrypted bytes.Buffer
+	if err := EncryptStream(bytes.NewReader(plaintext), &encrypted, []byte("pw"), testKDFParams()); err != nil {
+		t.Fatalf("EncryptStream失败: %v", err)
+	}
+
+	full := encrypted.Bytes()
+	truncated := full[:len(full)-1]
+
+	var decrypted bytes.Buffer
+	if err := DecryptStream(bytes.NewReader(truncated), &decrypted, []byte("pw"), testKDFParams()); err == nil {
+		t.Fatal("在密文尾部截断一字节应当被拒绝，但DecryptStream返回了nil")
+	}
+
+	// 单帧消息截在帧头之前（模拟在帧边界处被切断）：读不到末帧标记，
+	// 必须报错而不是被当成合法的空结尾静默放行。
+	headerOnly := full[:streamHeaderSize(t)]
+
+	var decryptedAtBoundary bytes.Buffer
+	err := DecryptStream(bytes.NewReader(headerOnly), &decryptedAtBoundary, []byte("pw"), testKDFParams())
+	if err == nil {
+		t.Fatal("在帧边界处截断的流应当被拒绝，但DecryptStream返回了nil")
+	}
+	if !strings.Contains(err.Error(), "截断") {
+		t.Fatalf("期望错误信息提及截断，got: %v", err)
+	}
+}
+
+// streamHeaderSize 返回流加密文件头的字节长度：magic + algo(1) + salt +
+// baseNonce，用于在测试中截出"恰好只有文件头、没有任何帧"的输入。
+func streamHeaderSize(t *testing.T) int {
+	t.Helper()
+	return len(streamMagic) + 1 + streamSaltSize + streamNonceSize
+}
+
+func TestDecryptStreamRejectsTamperedFrame(t *testing.T) {
+	plaintext := []byte("tamper me if you can")
+
+	var encrypted bytes.Buffer
+	if err := EncryptStream(bytes.NewReader(plaintext), &encrypted, []byte("pw"), testKDFParams()); err != nil {
+		t.Fatalf("EncryptStream失败: %v", err)
+	}
+
+	tampered := encrypted.Bytes()
+	tampered[len(tampered)-1] ^= 0xFF
+
+	var decrypted bytes.Buffer
+	err := DecryptStream(bytes.NewReader(tampered), &decrypted, []byte("pw"), testKDFParams())
+	if err == nil {
+		t.Fatal("篡改过密文的帧应当解密失败，但返回了nil")
+	}
+}
@@ -1,33 +1,61 @@
 package main
 
 import (
+	"bufio"
+	"crypto/ed25519"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"synccli/remote"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	remoteConfigName string
-	remoteHost       string
-	remotePort       int
-	remoteUser       string
-	remotePassword   string
-	remoteKeyFile    string
-	remoteBasePath   string
-	syncDirection    string
-	dryRun           bool
-	force            bool
-	verbose          bool
-	progress         bool
-	deleteEctra      bool
-	compression      bool
-	encryption       bool
-	incremental      bool
-	knownHostsFile   string
-	strictHostCheck  bool
+	remoteConfigName     string
+	remoteHost           string
+	remotePort           int
+	remoteUser           string
+	remotePassword       string
+	remoteKeyFile        string
+	remoteBasePath       string
+	remoteProtocol       string
+	syncDirection        string
+	dryRun               bool
+	force                bool
+	verbose              bool
+	progress             bool
+	deleteEctra          bool
+	compression          bool
+	encryption           bool
+	encryptionPassphrase string
+	incremental          bool
+	knownHostsFile       string
+	strictHostCheck      bool
+	usePythonScanner     bool
+	assumeYes            bool
+	noStrictHost         bool
+
+	watchDebounceMS      int
+	watchMaxBatchSize    int
+	watchIgnoreHiddenDot bool
+
+	backendAddr   string
+	backendPrefix string
+
+	bundleNames             []string
+	bundleRedactSecrets     bool
+	bundleIncludeKeyFiles   bool
+	bundleIncludeKnownHosts bool
+	bundleSigningKeyFile    string
+	bundleConflict          string
+	bundleVerifyKeyFile     string
+
+	remoteExtends string
+	remoteTarget  string
 )
 
 // 远程同步根命令
@@ -52,13 +80,14 @@ var remoteConfigAddCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configName := args[0]
-		cm, err := remote.NewConfigManager()
+		cm, err := loadConfigManager()
 		if err != nil {
-			return fmt.Errorf("Errorf: %v", err)
+			return err
 		}
 
 		config := &remote.RemoteConfig{
-			Name: configName,
+			Name:     configName,
+			Protocol: remoteProtocol,
 			SSH: &remote.SSHConfig{
 				Host:     remoteHost,
 				Port:     remotePort,
@@ -71,6 +100,8 @@ var remoteConfigAddCmd = &cobra.Command{
 			Compression: compression,
 			Encryption:  encryption,
 			Incremental: incremental,
+			Extends:     remoteExtends,
+			Target:      remoteTarget,
 			ExcludeList: []string{
 				".git", ".DS_Store", "*.tmp", "*.log",
 				"node_modules", "__pycache__", "target",
@@ -78,6 +109,13 @@ var remoteConfigAddCmd = &cobra.Command{
 		}
 
 		if err := cm.ValidateConfig(config); err != nil {
+			if valErr, ok := err.(*remote.ConfigValidationError); ok {
+				fmt.Println("config validation failed:")
+				for _, fieldErr := range valErr.Errors {
+					fmt.Printf(" - %s\n", fieldErr.String())
+				}
+				return fmt.Errorf("config validation failed: %d field(s) invalid", len(valErr.Errors))
+			}
 			return fmt.Errorf("Errorf: %v", err)
 		}
 		if err := cm.AddConfig(config); err != nil {
@@ -95,9 +133,9 @@ var remoteConfigListCmd = &cobra.Command{
 	Short: "List all remote configurations.",
 	Long:  `List all saved remote synchronization configurations.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cm, err := remote.NewConfigManager()
+		cm, err := loadConfigManager()
 		if err != nil {
-			return fmt.Errorf("Errorf: %v", err)
+			return err
 		}
 
 		configs := cm.ListConfigs()
@@ -128,9 +166,9 @@ var remoteConfigRemoveCmd = &cobra.Command{
 	Long:  `Delete the specified remote synchronization configuration.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configName := args[0]
-		cm, err := remote.NewConfigManager()
+		cm, err := loadConfigManager()
 		if err != nil {
-			return fmt.Errorf("Errorf: %v", err)
+			return err
 		}
 		if err := cm.RemoveConfig(configName); err != nil {
 			return fmt.Errorf("Errorf: %v", err)
@@ -141,6 +179,55 @@ var remoteConfigRemoveCmd = &cobra.Command{
 	},
 }
 
+// 配置模板管理命令
+var remoteConfigTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage config templates used by 'remote config add --extends'.",
+	Long:  `Manage reusable config templates that other configs can inherit unset fields from via Extends.`,
+}
+
+// 添加配置模板命令
+var remoteConfigTemplateAddCmd = &cobra.Command{
+	Use:   "add []",
+	Short: "Add or overwrite a config template.",
+	Long:  `Add or overwrite a config template that 'remote config add --extends <name>' can inherit from.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templateName := args[0]
+		cm, err := loadConfigManager()
+		if err != nil {
+			return err
+		}
+
+		template := &remote.RemoteConfig{
+			Protocol: remoteProtocol,
+			SSH: &remote.SSHConfig{
+				Host:     remoteHost,
+				Port:     remotePort,
+				Username: remoteUser,
+				Password: remotePassword,
+				KeyFile:  remoteKeyFile,
+				Timeout:  30,
+			},
+			RemoteBase:  remoteBasePath,
+			Compression: compression,
+			Encryption:  encryption,
+			Incremental: incremental,
+			ExcludeList: []string{
+				".git", ".DS_Store", "*.tmp", "*.log",
+				"node_modules", "__pycache__", "target",
+			},
+		}
+
+		if err := cm.AddTemplate(templateName, template); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Printf("Add over : %s\n", templateName)
+		return nil
+	},
+}
+
 // 远程同步命令
 var remoteSyncCmd = &cobra.Command{
 	Use:   "sync [] []",
@@ -160,9 +247,9 @@ var remoteSyncCmd = &cobra.Command{
 		var err error
 
 		if remoteConfigName != "" {
-			cm, err := remote.NewConfigManager()
+			cm, err := loadConfigManager()
 			if err != nil {
-				return fmt.Errorf("Errorf: %v", err)
+				return err
 			}
 
 			config, err = cm.GetConfig(remoteConfigName)
@@ -174,7 +261,8 @@ var remoteSyncCmd = &cobra.Command{
 				return fmt.Errorf("no hostname or username")
 			}
 			config = &remote.RemoteConfig{
-				Name: "Temporary configuration.",
+				Name:     "Temporary configuration.",
+				Protocol: remoteProtocol,
 				SSH: &remote.SSHConfig{
 					Host:            remoteHost,
 					Port:            remotePort,
@@ -189,6 +277,7 @@ var remoteSyncCmd = &cobra.Command{
 				Compression: compression,
 				Encryption:  encryption,
 				Incremental: incremental,
+				Target:      remoteTarget,
 				ExcludeList: []string{
 					".git", ".DS_Store", "*.tmp", "*.log",
 					"node_modules", "__pycache__", "target",
@@ -210,14 +299,18 @@ var remoteSyncCmd = &cobra.Command{
 		}
 
 		options := &remote.SyncOptions{
-			Direction:   direction,
-			DryRun:      dryRun,
-			Force:       force,
-			Verbose:     verbose,
-			Progress:    progress,
-			DeleteExtra: deleteEctra,
+			Direction:            direction,
+			DryRun:               dryRun,
+			Force:                force,
+			Verbose:              verbose,
+			Progress:             progress,
+			DeleteExtra:          deleteEctra,
+			UsePythonScanner:     usePythonScanner,
+			EncryptionPassphrase: encryptionPassphrase,
 		}
 
+		applyHostKeyPolicy(config.SSH)
+
 		engine := remote.NewRemoteSyncEngine(config, options)
 
 		fmt.Printf("connect %s@%s:%d...\n", config.SSH.Username, config.SSH.Host, config.SSH.Port)
@@ -264,9 +357,9 @@ var remoteTestCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configName := args[0]
-		cm, err := remote.NewConfigManager()
+		cm, err := loadConfigManager()
 		if err != nil {
-			return fmt.Errorf("Errorf: %v", err)
+			return err
 		}
 
 		config, err := cm.GetConfig(configName)
@@ -274,7 +367,9 @@ var remoteTestCmd = &cobra.Command{
 			return fmt.Errorf("Errorf: %v", err)
 		}
 
-		fmt.Printf("test %s@%s:%d...\n", config.SSH.Username, config.SSH.Port)
+		fmt.Printf("test %s@%s:%d...\n", config.SSH.Username, config.SSH.Host, config.SSH.Port)
+
+		applyHostKeyPolicy(config.SSH)
 
 		client := remote.NewSSHClient(config.SSH)
 		if err := client.Connect(); err != nil {
@@ -286,11 +381,386 @@ var remoteTestCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("Errorf: %v", err)
 		}
-		fmt.Println("echo: %s", output)
+		fmt.Printf("echo: %s\n", output)
+		return nil
+	},
+}
+
+// 监视同步命令
+var remoteWatchCmd = &cobra.Command{
+	Use:   "watch [localPath] [remotePath]",
+	Short: "Watch a local directory and sync changes incrementally.",
+	Long:  `Perform an initial full sync, then watch the local directory and push incremental changes to the remote as they happen.`,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		localPath := args[0]
+		var remotePath string
+		if len(args) > 1 {
+			remotePath = args[1]
+		} else {
+			remotePath = filepath.Base(localPath)
+		}
+
+		var config *remote.RemoteConfig
+		var configUpdates <-chan remote.ConfigEvent
+
+		if remoteConfigName != "" {
+			cm, err := loadConfigManager()
+			if err != nil {
+				return err
+			}
+
+			config, err = cm.GetConfig(remoteConfigName)
+			if err != nil {
+				return fmt.Errorf("Errorf: %v", err)
+			}
+
+			if ch, err := cm.Subscribe(); err != nil {
+				fmt.Printf("警告: 无法订阅配置变更，本次 watch 期间配置更新不会热加载: %v\n", err)
+			} else {
+				configUpdates = ch
+			}
+		} else {
+			if remoteHost == "" || remoteUser == "" {
+				return fmt.Errorf("no hostname or username")
+			}
+			config = &remote.RemoteConfig{
+				Name:     "Temporary configuration.",
+				Protocol: remoteProtocol,
+				SSH: &remote.SSHConfig{
+					Host:            remoteHost,
+					Port:            remotePort,
+					Username:        remoteUser,
+					Password:        remotePassword,
+					KeyFile:         remoteKeyFile,
+					Timeout:         30,
+					KnownHostsFile:  knownHostsFile,
+					StrictHostCheck: strictHostCheck,
+				},
+				RemoteBase:  remoteBasePath,
+				Compression: compression,
+				Encryption:  encryption,
+				Incremental: incremental,
+				Target:      remoteTarget,
+				ExcludeList: []string{
+					".git", ".DS_Store", "*.tmp", "*.log",
+					"node_modules", "__pycache__", "target",
+				},
+			}
+		}
+
+		options := &remote.SyncOptions{
+			Direction:            remote.SyncToRemote,
+			Verbose:              verbose,
+			Progress:             progress,
+			UsePythonScanner:     usePythonScanner,
+			EncryptionPassphrase: encryptionPassphrase,
+		}
+
+		applyHostKeyPolicy(config.SSH)
+
+		engine := remote.NewRemoteSyncEngine(config, options)
+
+		fmt.Printf("connect %s@%s:%d...\n", config.SSH.Username, config.SSH.Host, config.SSH.Port)
+		if err := engine.Connect(); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+		defer engine.Disconnect()
+
+		watchOptions := remote.WatchOptions{
+			DebounceMS:      watchDebounceMS,
+			MaxBatchSize:    watchMaxBatchSize,
+			IgnoreHiddenDot: watchIgnoreHiddenDot,
+			ConfigName:      remoteConfigName,
+			ConfigUpdates:   configUpdates,
+		}
+
+		return engine.Watch(localPath, remotePath, watchOptions)
+	},
+}
+
+// loadConfigManager 打开本地远程配置仓库；如果仓库已启用加密且当前处于
+// 锁定状态，会交互式地提示输入主口令来解锁，解锁成功后口令会被缓存进
+// 系统密钥串，后续调用不再重复提示。
+func loadConfigManager() (*remote.ConfigManager, error) {
+	cm, err := remote.NewConfigManager(remote.StoreOption{})
+	if err != nil {
+		return nil, fmt.Errorf("Errorf: %v", err)
+	}
+
+	if cm.IsLocked() {
+		passphrase, err := promptVaultPassphrase("配置仓库已加密，请输入主口令: ")
+		if err != nil {
+			return nil, err
+		}
+		if err := cm.Unlock(passphrase); err != nil {
+			return nil, fmt.Errorf("Errorf: %v", err)
+		}
+	}
+
+	return cm, nil
+}
+
+// promptVaultPassphrase 从终端读取主口令。标准输入是终端时用
+// term.ReadPassword 关闭回显，避免口令被打在屏幕上或留在终端回滚缓冲区里；
+// 标准输入被重定向（管道/脚本喂入，没有回显可关）时退回逐行读取。
+func promptVaultPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("读取口令失败: %v", err)
+		}
+		return strings.TrimSpace(string(passphrase)), nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("读取口令失败: %v", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// 配置仓库加密管理命令
+var remoteConfigVaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage at-rest encryption of the remote config file.",
+	Long:  `Unlock, lock, or change the master passphrase protecting ~/.synccli/remote_configs.json.`,
+}
+
+var remoteConfigVaultUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock the config vault, or enable encryption for the first time.",
+	Long:  `Enable at-rest encryption for the remote config file (if not already enabled) and unlock it for this session.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := remote.NewConfigManager(remote.StoreOption{})
+		if err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		passphrase, err := promptVaultPassphrase("master passphrase: ")
+		if err != nil {
+			return err
+		}
+		if err := cm.Unlock(passphrase); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+		if err := cm.SaveConfigs(); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Println("vault unlocked")
+		return nil
+	},
+}
+
+var remoteConfigVaultLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Forget the cached passphrase and require it again next time.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := remote.NewConfigManager(remote.StoreOption{})
+		if err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+		if err := cm.Lock(); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+		fmt.Println("vault locked")
+		return nil
+	},
+}
+
+var remoteConfigVaultPasswdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Change the master passphrase protecting the config vault.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := remote.NewConfigManager(remote.StoreOption{})
+		if err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		oldPassphrase, err := promptVaultPassphrase("current passphrase: ")
+		if err != nil {
+			return err
+		}
+		if err := cm.Unlock(oldPassphrase); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		newPassphrase, err := promptVaultPassphrase("new passphrase: ")
+		if err != nil {
+			return err
+		}
+		if err := cm.ChangePassphrase(oldPassphrase, newPassphrase); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Println("passphrase changed")
+		return nil
+	},
+}
+
+// 配置后端迁移命令
+var remoteConfigBackendCmd = &cobra.Command{
+	Use:   "backend",
+	Short: "Manage which storage backend remote configs are persisted in.",
+	Long:  `Inspect or switch the storage backend (json/ini/yaml/env/etcd/consul/redis) used to persist remote configs.`,
+}
+
+var remoteConfigBackendSetCmd = &cobra.Command{
+	Use:   "set <type>",
+	Short: "Migrate all remote configs to a different storage backend.",
+	Long:  `Read all remote configs from the currently selected backend, write them into the backend identified by <type>, and make it the new default for future commands.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := loadConfigManager()
+		if err != nil {
+			return err
+		}
+
+		opt := remote.StoreOption{
+			Type:   remote.StoreType(args[0]),
+			Addr:   backendAddr,
+			Prefix: backendPrefix,
+		}
+		if err := cm.MigrateStore(opt); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Printf("backend switched to: %s\n", args[0])
+		return nil
+	},
+}
+
+// 配置打包/导入命令
+var remoteConfigBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export or import remote configs as a signed tar.gz bundle.",
+	Long:  `Package a set of remote configs (optionally with their key files and known_hosts) into a tar.gz bundle for sharing with teammates, or import one produced this way.`,
+}
+
+var remoteConfigBundleExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export remote configs to a bundle file.",
+	Long:  `Export the named configs (or all of them, if --name is not given) into a tar.gz bundle written to <file>.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := loadConfigManager()
+		if err != nil {
+			return err
+		}
+
+		opts := remote.ExportOptions{
+			RedactSecrets:     bundleRedactSecrets,
+			IncludeKeyFiles:   bundleIncludeKeyFiles,
+			IncludeKnownHosts: bundleIncludeKnownHosts,
+		}
+		if bundleSigningKeyFile != "" {
+			key, err := os.ReadFile(bundleSigningKeyFile)
+			if err != nil {
+				return fmt.Errorf("读取签名私钥失败: %v", err)
+			}
+			opts.SigningKey = ed25519.PrivateKey(key)
+		}
+
+		out, err := os.Create(args[0])
+		if err != nil {
+			return fmt.Errorf("创建bundle文件失败: %v", err)
+		}
+		defer out.Close()
+
+		if err := cm.ExportBundle(bundleNames, out, opts); err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Printf("bundle exported to: %s\n", args[0])
+		return nil
+	},
+}
+
+var remoteConfigBundleImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import remote configs from a bundle file.",
+	Long:  `Import the configs contained in the bundle <file>, applying --conflict to any name clashes with existing configs.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := loadConfigManager()
+		if err != nil {
+			return err
+		}
+
+		opts := remote.ImportOptions{Conflict: remote.ImportConflictPolicy(bundleConflict)}
+		if bundleVerifyKeyFile != "" {
+			key, err := os.ReadFile(bundleVerifyKeyFile)
+			if err != nil {
+				return fmt.Errorf("读取验签公钥失败: %v", err)
+			}
+			opts.VerifyKey = ed25519.PublicKey(key)
+		}
+
+		in, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("打开bundle文件失败: %v", err)
+		}
+		defer in.Close()
+
+		imported, err := cm.ImportBundle(in, opts)
+		if err != nil {
+			return fmt.Errorf("Errorf: %v", err)
+		}
+
+		fmt.Printf("imported %d config(s): %s\n", len(imported), strings.Join(imported, ", "))
 		return nil
 	},
 }
 
+// applyHostKeyPolicy 根据 --yes/--no-strict 全局标志，为 sshConfig 配置
+// 未知主机密钥的信任策略：
+//
+//   - --no-strict：完全关闭主机密钥校验（等价于把 StrictHostCheck 设为
+//     false），用于测试环境或明知风险的自动化场景。
+//   - --yes：遇到未知主机自动信任并写入 known_hosts，不做任何交互式
+//     确认，用于 CI/CD 等非交互式自动化场景。
+//   - 默认：遇到未知主机时在终端打印指纹，等待用户确认。
+func applyHostKeyPolicy(sshConfig *remote.SSHConfig) {
+	if noStrictHost {
+		sshConfig.StrictHostCheck = false
+		return
+	}
+	if assumeYes {
+		sshConfig.HostKeyPrompt = func(info remote.UnknownHostError) (bool, bool, error) {
+			fmt.Printf("自动信任未知主机 %s（--yes）：%s 密钥指纹 %s\n", info.Hostname, info.KeyType, info.Fingerprint)
+			return true, true, nil
+		}
+		return
+	}
+	sshConfig.HostKeyPrompt = terminalHostKeyPrompt
+}
+
+// terminalHostKeyPrompt 是默认的交互式主机密钥确认实现：打印主机名、
+// 密钥类型与 SHA256 指纹，请用户确认是否信任，并询问是否记住该主机。
+func terminalHostKeyPrompt(info remote.UnknownHostError) (accept bool, persist bool, err error) {
+	fmt.Printf("无法确认主机 %s (%s) 的真实性。\n", info.Hostname, info.RemoteAddr)
+	fmt.Printf("%s 密钥指纹为 %s\n", info.KeyType, info.Fingerprint)
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("是否信任并继续连接？[y/N] ")
+	answer, _ := reader.ReadString('\n')
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y") {
+		return false, false, nil
+	}
+
+	fmt.Print("是否记住该主机密钥，以后不再询问？[Y/n] ")
+	answer, _ = reader.ReadString('\n')
+	persist = !strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "n")
+
+	return true, persist, nil
+}
+
 // 格式化字节数
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -309,17 +779,46 @@ func init() {
 	// Add remote commands to root command
 	rootCmd.AddCommand(remoteCmd)
 
+	// Host key trust policy, shared by every subcommand that connects
+	remoteCmd.PersistentFlags().BoolVar(&assumeYes, "yes", false, "Automatically trust and persist unknown host keys without prompting (for automation)")
+	remoteCmd.PersistentFlags().BoolVar(&noStrictHost, "no-strict", false, "Disable host key verification entirely (insecure, for test environments)")
+
 	// Add subcommands
 	remoteCmd.AddCommand(remoteConfigCmd)
 	remoteCmd.AddCommand(remoteSyncCmd)
 	remoteCmd.AddCommand(remoteTestCmd)
+	remoteCmd.AddCommand(remoteWatchCmd)
 
 	// Add configuration management subcommands
 	remoteConfigCmd.AddCommand(remoteConfigAddCmd)
 	remoteConfigCmd.AddCommand(remoteConfigListCmd)
 	remoteConfigCmd.AddCommand(remoteConfigRemoveCmd)
+	remoteConfigCmd.AddCommand(remoteConfigVaultCmd)
+	remoteConfigVaultCmd.AddCommand(remoteConfigVaultUnlockCmd)
+	remoteConfigVaultCmd.AddCommand(remoteConfigVaultLockCmd)
+	remoteConfigVaultCmd.AddCommand(remoteConfigVaultPasswdCmd)
+	remoteConfigCmd.AddCommand(remoteConfigBackendCmd)
+	remoteConfigBackendCmd.AddCommand(remoteConfigBackendSetCmd)
+	remoteConfigCmd.AddCommand(remoteConfigBundleCmd)
+	remoteConfigBundleCmd.AddCommand(remoteConfigBundleExportCmd)
+	remoteConfigBundleCmd.AddCommand(remoteConfigBundleImportCmd)
+	remoteConfigCmd.AddCommand(remoteConfigTemplateCmd)
+	remoteConfigTemplateCmd.AddCommand(remoteConfigTemplateAddCmd)
+
+	remoteConfigBackendSetCmd.Flags().StringVar(&backendAddr, "addr", "", "Backend address (etcd/consul/redis only)")
+	remoteConfigBackendSetCmd.Flags().StringVar(&backendPrefix, "prefix", "", "Key prefix (etcd/consul/redis only)")
+
+	remoteConfigBundleExportCmd.Flags().StringSliceVar(&bundleNames, "name", nil, "Config name to include (repeatable; default: all configs)")
+	remoteConfigBundleExportCmd.Flags().BoolVar(&bundleRedactSecrets, "redact-secrets", false, "Clear SSH passwords before packaging")
+	remoteConfigBundleExportCmd.Flags().BoolVar(&bundleIncludeKeyFiles, "include-key-files", false, "Package the referenced SSH private key files too")
+	remoteConfigBundleExportCmd.Flags().BoolVar(&bundleIncludeKnownHosts, "include-known-hosts", false, "Package the referenced known_hosts files too")
+	remoteConfigBundleExportCmd.Flags().StringVar(&bundleSigningKeyFile, "sign-key", "", "Path to a raw Ed25519 private key file to sign the bundle with")
+
+	remoteConfigBundleImportCmd.Flags().StringVar(&bundleConflict, "conflict", string(remote.ImportSkip), "How to handle name clashes with existing configs: skip|overwrite|rename")
+	remoteConfigBundleImportCmd.Flags().StringVar(&bundleVerifyKeyFile, "verify-key", "", "Path to a raw Ed25519 public key file; reject the bundle if it isn't signed with the matching private key")
 
 	// Configure add command parameters
+	remoteConfigAddCmd.Flags().StringVar(&remoteProtocol, "protocol", remote.ProtocolSSH, "Transport protocol (ssh/ftp/ftps/webdav)")
 	remoteConfigAddCmd.Flags().StringVar(&remoteHost, "host", "", "Remote host address (required)")
 	remoteConfigAddCmd.Flags().IntVar(&remotePort, "port", 22, "SSH port")
 	remoteConfigAddCmd.Flags().StringVar(&remoteUser, "user", "", "SSH username (required)")
@@ -329,12 +828,27 @@ func init() {
 	remoteConfigAddCmd.Flags().BoolVar(&compression, "compression", true, "Enable compression")
 	remoteConfigAddCmd.Flags().BoolVar(&encryption, "encryption", true, "Enable encryption")
 	remoteConfigAddCmd.Flags().BoolVar(&incremental, "incremental", true, "Enable incremental sync")
+	remoteConfigAddCmd.Flags().StringVar(&remoteExtends, "extends", "", "Name of a template (see 'remote config template add') this config inherits unset fields from")
+	remoteConfigAddCmd.Flags().StringVar(&remoteTarget, "target", "", "Target URL overriding --protocol for the transfer/scan backend (sftp://, s3://, s3s://, webdav://, webdavs://, file://); connection params still come from --host/--user/--password")
+
+	// Configure template add command parameters (mirrors config add, minus --extends: templates don't nest; --target is not offered either)
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remoteProtocol, "protocol", remote.ProtocolSSH, "Transport protocol (ssh/ftp/ftps/webdav)")
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remoteHost, "host", "", "Remote host address")
+	remoteConfigTemplateAddCmd.Flags().IntVar(&remotePort, "port", 22, "SSH port")
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remoteUser, "user", "", "SSH username")
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remotePassword, "password", "", "SSH password")
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remoteKeyFile, "key", "", "SSH private key file path")
+	remoteConfigTemplateAddCmd.Flags().StringVar(&remoteBasePath, "base", "/tmp/synccli", "Remote base path")
+	remoteConfigTemplateAddCmd.Flags().BoolVar(&compression, "compression", true, "Enable compression")
+	remoteConfigTemplateAddCmd.Flags().BoolVar(&encryption, "encryption", true, "Enable encryption")
+	remoteConfigTemplateAddCmd.Flags().BoolVar(&incremental, "incremental", true, "Enable incremental sync")
 
 	remoteConfigAddCmd.MarkFlagRequired("host")
 	remoteConfigAddCmd.MarkFlagRequired("user")
 
 	// Remote sync command parameters
 	remoteSyncCmd.Flags().StringVar(&remoteConfigName, "config", "", "Use saved configuration name")
+	remoteSyncCmd.Flags().StringVar(&remoteProtocol, "protocol", remote.ProtocolSSH, "Transport protocol (ssh/ftp/ftps/webdav)")
 	remoteSyncCmd.Flags().StringVar(&remoteHost, "host", "", "Remote host address")
 	remoteSyncCmd.Flags().IntVar(&remotePort, "port", 22, "SSH port")
 	remoteSyncCmd.Flags().StringVar(&remoteUser, "user", "", "SSH username")
@@ -352,5 +866,28 @@ func init() {
 	remoteSyncCmd.Flags().BoolVar(&deleteEctra, "delete", false, "Delete extra files")
 	remoteSyncCmd.Flags().BoolVar(&compression, "compression", true, "Enable compression")
 	remoteSyncCmd.Flags().BoolVar(&encryption, "encryption", true, "Enable encryption")
+	remoteSyncCmd.Flags().StringVar(&encryptionPassphrase, "encryption-passphrase", "", "Passphrase used to derive the stream encryption key when --encryption is set; files are transferred in plaintext if empty")
 	remoteSyncCmd.Flags().BoolVar(&incremental, "incremental", true, "Enable incremental sync")
+	remoteSyncCmd.Flags().BoolVar(&usePythonScanner, "use-python-scanner", false, "Use the legacy Python subprocess scanner instead of the built-in Go scanner")
+	remoteSyncCmd.Flags().StringVar(&remoteTarget, "target", "", "Target URL overriding --protocol for the transfer/scan backend (sftp://, s3://, s3s://, webdav://, webdavs://, file://); connection params still come from --host/--user/--password")
+
+	// Watch command parameters
+	remoteWatchCmd.Flags().StringVar(&remoteConfigName, "config", "", "Use saved configuration name")
+	remoteWatchCmd.Flags().StringVar(&remoteProtocol, "protocol", remote.ProtocolSSH, "Transport protocol (ssh/ftp/ftps/webdav)")
+	remoteWatchCmd.Flags().StringVar(&remoteHost, "host", "", "Remote host address")
+	remoteWatchCmd.Flags().IntVar(&remotePort, "port", 22, "SSH port")
+	remoteWatchCmd.Flags().StringVar(&remoteUser, "user", "", "SSH username")
+	remoteWatchCmd.Flags().StringVar(&remotePassword, "password", "", "SSH password")
+	remoteWatchCmd.Flags().StringVar(&remoteKeyFile, "key", "", "SSH private key file path")
+	remoteWatchCmd.Flags().StringVar(&remoteBasePath, "base", "/tmp/synccli", "Remote base path")
+	remoteWatchCmd.Flags().StringVar(&knownHostsFile, "known-hosts", "", "known_hosts file path (default: ~/.ssh/known_hosts)")
+	remoteWatchCmd.Flags().BoolVar(&strictHostCheck, "strict-host-check", true, "Enable strict host key checking")
+	remoteWatchCmd.Flags().StringVar(&encryptionPassphrase, "encryption-passphrase", "", "Passphrase used to derive the stream encryption key when the config has encryption enabled; files are transferred in plaintext if empty")
+	remoteWatchCmd.Flags().BoolVar(&verbose, "verbose", false, "Show detailed information")
+	remoteWatchCmd.Flags().BoolVar(&progress, "progress", true, "Show progress bar")
+	remoteWatchCmd.Flags().BoolVar(&usePythonScanner, "use-python-scanner", false, "Use the legacy Python subprocess scanner instead of the built-in Go scanner")
+	remoteWatchCmd.Flags().IntVar(&watchDebounceMS, "debounce", 500, "Debounce window in milliseconds for batching changes")
+	remoteWatchCmd.Flags().IntVar(&watchMaxBatchSize, "max-batch-size", 200, "Maximum number of changes per incremental sync batch")
+	remoteWatchCmd.Flags().BoolVar(&watchIgnoreHiddenDot, "ignore-hidden", true, "Ignore dot-prefixed hidden files and directories")
+	remoteWatchCmd.Flags().StringVar(&remoteTarget, "target", "", "Target URL overriding --protocol for the transfer/scan backend (sftp://, s3://, s3s://, webdav://, webdavs://, file://); connection params still come from --host/--user/--password")
 }
@@ -115,7 +115,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("rules is err: %v\n", err)
 		}
 		filesToSync = FilterResult.FilteredFiles
-		fmt.Println("Filtering completed - Files to synchronize: %d\n", len(filesToSync))
+		fmt.Printf("Filtering completed - Files to synchronize: %d\n", len(filesToSync))
 	}
 
 	// 3.Rust差异计算
@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// BackendFileInfo 与 TransportFileInfo 结构相同（按路径、大小、修改时间、
+// 是否为目录描述一个远程条目），这里用别名而非另起一套字段，避免
+// Backend 与 Transport 两套抽象在调用方眼里变成互不相干的两种类型。
+type BackendFileInfo = TransportFileInfo
+
+// Backend 是比 Transport 更贴近文件句柄语义的远程存储抽象：Open/Create
+// 直接返回 io.ReadCloser/io.WriteCloser，而不是像 Transport.Put/Get 那样
+// 只接受本地路径。这对接 stream_crypto.go 的 EncryptStream/DecryptStream
+// 或任何需要边读边处理而不是先落盘的调用方更自然。
+//
+// SSHClient、本地文件系统、S3 兼容对象存储、WebDAV 都实现该接口，
+// 通过 NewBackend 按 URL scheme（sftp/s3/file/webdav）选择具体实现。
+type Backend interface {
+	Stat(path string) (BackendFileInfo, error)
+	Open(path string) (io.ReadCloser, error)
+	Create(path string) (io.WriteCloser, error)
+	List(path string) ([]BackendFileInfo, error)
+	Remove(path string) error
+	Mkdir(path string) error
+}
+
+// backendScheme 是 NewBackend 支持的 URL scheme。s3s/webdavs 与
+// s3/webdav 的区别只是走 TLS，和 ftp/ftps 是同一个选型（参见
+// transport_ftp.go 的 explicitTLS）。
+const (
+	backendSchemeSFTP         = "sftp"
+	backendSchemeS3           = "s3"
+	backendSchemeS3Secure     = "s3s"
+	backendSchemeFile         = "file"
+	backendSchemeWebDAV       = "webdav"
+	backendSchemeWebDAVSecure = "webdavs"
+)
+
+// NewBackend 按 rawURL 的 scheme 选择具体的 Backend 实现：
+//
+//   - sftp://   复用已连接的 sshClient（必须非 nil）
+//   - file://   本地文件系统，host 部分被忽略，path 即根目录
+//   - webdav:// / webdavs:// 复用 config.SSH 的 Host/Port/Username/Password，
+//     webdavs 走 TLS
+//   - s3:// / s3s:// 复用 config.SSH 的 Host 作为 endpoint、Username/Password
+//     作为 AccessKey/SecretKey，path 的第一段作为 bucket，s3s 走 TLS
+//
+// 这与 newTransport 按 RemoteConfig.Protocol 选择 Transport 是两条并行
+// 的选型路径：Transport 面向"同步计划的批量执行"，Backend 面向"单个
+// 文件的流式读写"，两者目前各自独立实现对应协议的连接细节，尚未合并
+// 成单一的连接管理层。RemoteConfig.Target 非空时，RemoteSyncEngine 用
+// 这里选出的 Backend 驱动实际的远程目录扫描与文件传输（见
+// sync_engine.go 的 scanRemoteFilesBackend、transport.go 的
+// executeSyncPlanBackend），而不只是 encrypted_transfer.go 里加密
+// 上传/下载这一条路径。
+func NewBackend(rawURL string, config *RemoteConfig, sshClient *SSHClient) (Backend, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析后端URL失败 %s: %v", rawURL, err)
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case backendSchemeSFTP, "":
+		if sshClient == nil {
+			return nil, fmt.Errorf("sftp后端需要已连接的SSHClient")
+		}
+		return sshClient, nil
+	case backendSchemeFile:
+		return NewLocalBackend(parsed.Path), nil
+	case backendSchemeWebDAV:
+		return NewWebDAVBackend(config.SSH, false), nil
+	case backendSchemeWebDAVSecure:
+		return NewWebDAVBackend(config.SSH, true), nil
+	case backendSchemeS3:
+		bucket, prefix := splitS3Path(parsed.Path)
+		return NewS3Backend(config.SSH, bucket, prefix, false)
+	case backendSchemeS3Secure:
+		bucket, prefix := splitS3Path(parsed.Path)
+		return NewS3Backend(config.SSH, bucket, prefix, true)
+	default:
+		return nil, fmt.Errorf("不支持的后端URL scheme: %s", parsed.Scheme)
+	}
+}
+
+// walkBackend 递归遍历一个 Backend 上的远程目录树，用于 S3/WebDAV/本地
+// 文件系统这类没有 SFTP Walk 等价物的后端；与 transport.go 的
+// walkTransport 是同一个思路，只是面向 Backend.List 而不是 Transport.List。
+func walkBackend(b Backend, root string) ([]BackendFileInfo, error) {
+	var results []BackendFileInfo
+
+	entries, err := b.List(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir {
+			children, err := walkBackend(b, entry.Path)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, children...)
+			continue
+		}
+		results = append(results, entry)
+	}
+
+	return results, nil
+}
+
+// splitS3Path 把 s3:// URL 的 path 部分（例如 "/my-bucket/some/prefix"）
+// 拆成 bucket 与其下的前缀。
+func splitS3Path(urlPath string) (bucket, prefix string) {
+	trimmed := strings.TrimPrefix(urlPath, "/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	bucket = parts[0]
+	if len(parts) == 2 {
+		prefix = parts[1]
+	}
+	return bucket, prefix
+}
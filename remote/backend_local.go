@@ -0,0 +1,91 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalBackend 是 Backend 的本地文件系统实现，供 file:// scheme 选用，
+// 也便于在没有真实远程主机时本地调试同步流程。root 为空时，传入的
+// path 被当作绝对/相对路径直接使用；非空时，path 视为相对 root 的路径。
+type LocalBackend struct {
+	root string
+}
+
+// NewLocalBackend 创建一个以 root 为根目录的本地文件系统后端。
+func NewLocalBackend(root string) *LocalBackend {
+	return &LocalBackend{root: root}
+}
+
+func (b *LocalBackend) resolve(p string) string {
+	if b.root == "" {
+		return p
+	}
+	return filepath.Join(b.root, p)
+}
+
+func (b *LocalBackend) Stat(p string) (BackendFileInfo, error) {
+	info, err := os.Stat(b.resolve(p))
+	if err != nil {
+		return BackendFileInfo{}, fmt.Errorf("获取本地文件信息失败 %s: %v", p, err)
+	}
+	return BackendFileInfo{Path: p, Size: info.Size(), ModTime: info.ModTime(), IsDir: info.IsDir()}, nil
+}
+
+func (b *LocalBackend) Open(p string) (io.ReadCloser, error) {
+	file, err := os.Open(b.resolve(p))
+	if err != nil {
+		return nil, fmt.Errorf("打开本地文件失败 %s: %v", p, err)
+	}
+	return file, nil
+}
+
+func (b *LocalBackend) Create(p string) (io.WriteCloser, error) {
+	full := b.resolve(p)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, fmt.Errorf("创建本地目录失败 %s: %v", filepath.Dir(full), err)
+	}
+	file, err := os.Create(full)
+	if err != nil {
+		return nil, fmt.Errorf("创建本地文件失败 %s: %v", p, err)
+	}
+	return file, nil
+}
+
+func (b *LocalBackend) List(p string) ([]BackendFileInfo, error) {
+	entries, err := os.ReadDir(b.resolve(p))
+	if err != nil {
+		return nil, fmt.Errorf("列出本地目录失败 %s: %v", p, err)
+	}
+
+	infos := make([]BackendFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("获取本地目录项信息失败 %s: %v", entry.Name(), err)
+		}
+		infos = append(infos, BackendFileInfo{
+			Path:    filepath.Join(p, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			IsDir:   info.IsDir(),
+		})
+	}
+	return infos, nil
+}
+
+func (b *LocalBackend) Remove(p string) error {
+	if err := os.Remove(b.resolve(p)); err != nil {
+		return fmt.Errorf("删除本地文件失败 %s: %v", p, err)
+	}
+	return nil
+}
+
+func (b *LocalBackend) Mkdir(p string) error {
+	if err := os.MkdirAll(b.resolve(p), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败 %s: %v", p, err)
+	}
+	return nil
+}
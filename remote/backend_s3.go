@@ -0,0 +1,130 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Backend 是面向 S3 兼容对象存储（含 MinIO）的 Backend 实现，复用
+// SSHConfig 的 Host 作为 endpoint、Username/Password 作为 AccessKey/
+// SecretKey——与 FTPTransport/WebDAVTransport 复用 SSHConfig 字段的
+// 做法一致，避免为每个新协议单独定义一份连接配置结构体。
+//
+// 对象存储没有真正的目录概念：Mkdir 是空操作，List/Stat 按 "以 / 结尾
+// 的 key 前缀" 模拟目录语义。
+type S3Backend struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Backend 创建一个 S3 兼容后端，bucket 下的所有路径都会加上 prefix
+// 前缀（prefix 为空表示直接使用 bucket 根）。secure 为 true 时用 TLS
+// 连接 endpoint（对应 backendSchemeS3Secure 的 "s3s://"），否则是明文
+// HTTP，与 FTPTransport 的 explicitTLS 参数是同一个选型。
+func NewS3Backend(config *SSHConfig, bucket, prefix string, secure bool) (*S3Backend, error) {
+	endpoint := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.Username, config.Password, ""),
+		Secure: secure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建S3客户端失败: %v", err)
+	}
+
+	return &S3Backend{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (b *S3Backend) key(p string) string {
+	if b.prefix == "" {
+		return strings.TrimPrefix(p, "/")
+	}
+	return path.Join(b.prefix, strings.TrimPrefix(p, "/"))
+}
+
+func (b *S3Backend) Stat(p string) (BackendFileInfo, error) {
+	info, err := b.client.StatObject(context.Background(), b.bucket, b.key(p), minio.StatObjectOptions{})
+	if err != nil {
+		return BackendFileInfo{}, fmt.Errorf("获取S3对象信息失败 %s: %v", p, err)
+	}
+	return BackendFileInfo{Path: p, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+func (b *S3Backend) Open(p string) (io.ReadCloser, error) {
+	object, err := b.client.GetObject(context.Background(), b.bucket, b.key(p), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("打开S3对象失败 %s: %v", p, err)
+	}
+	return object, nil
+}
+
+// s3Writer 把 minio-go 要求"先知道长度或用 -1 走分片上传"的 PutObject
+// 适配成普通的 io.WriteCloser：写入内容经管道流向后台的 PutObject 调用。
+type s3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func (b *S3Backend) Create(p string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.client.PutObject(context.Background(), b.bucket, b.key(p), pr, -1, minio.PutObjectOptions{})
+		pr.Close()
+		done <- err
+	}()
+	return &s3Writer{pw: pw, done: done}, nil
+}
+
+func (b *S3Backend) List(p string) ([]BackendFileInfo, error) {
+	prefix := b.key(p)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var infos []BackendFileInfo
+	for object := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: false}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("列出S3对象失败 %s: %v", p, object.Err)
+		}
+		infos = append(infos, BackendFileInfo{
+			Path:    path.Join(p, strings.TrimPrefix(strings.TrimSuffix(object.Key, "/"), prefix)),
+			Size:    object.Size,
+			ModTime: object.LastModified,
+			IsDir:   strings.HasSuffix(object.Key, "/"),
+		})
+	}
+	return infos, nil
+}
+
+func (b *S3Backend) Remove(p string) error {
+	if err := b.client.RemoveObject(context.Background(), b.bucket, b.key(p), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("删除S3对象失败 %s: %v", p, err)
+	}
+	return nil
+}
+
+// Mkdir 是空操作：对象存储没有真正的目录，上传对象时前缀自然存在。
+func (b *S3Backend) Mkdir(p string) error {
+	return nil
+}
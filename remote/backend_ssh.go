@@ -0,0 +1,129 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// 以下方法让 *SSHClient 满足 Backend 接口，与它早已具备的
+// UploadFile/DownloadFile 等整份传输方法并存——Backend 这组方法面向
+// "我要一个文件句柄自己读写"的调用方（例如流式加解密），整份传输仍然
+// 走 UploadFile/DownloadFile 更省心。
+var _ Backend = (*SSHClient)(nil)
+
+// Stat 实现 Backend：返回远程路径的统一文件信息。
+func (c *SSHClient) Stat(remotePath string) (BackendFileInfo, error) {
+	if !c.connected {
+		return BackendFileInfo{}, fmt.Errorf("ssh未连接")
+	}
+
+	info, err := c.sftpClient.Stat(remotePath)
+	if err != nil {
+		return BackendFileInfo{}, fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+	return BackendFileInfo{
+		Path:    remotePath,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}, nil
+}
+
+// Open 实现 Backend：以只读方式打开远程文件，返回的句柄可直接喂给
+// stream_crypto.go 的 DecryptStream 等流式消费者。
+func (c *SSHClient) Open(remotePath string) (io.ReadCloser, error) {
+	if !c.connected {
+		return nil, fmt.Errorf("ssh未连接")
+	}
+	file, err := c.sftpClient.Open(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	return file, nil
+}
+
+// Create 实现 Backend：创建（或覆盖）远程文件，返回的句柄可直接作为
+// EncryptStream 等流式生产者的写入目标。
+func (c *SSHClient) Create(remotePath string) (io.WriteCloser, error) {
+	if !c.connected {
+		return nil, fmt.Errorf("ssh未连接")
+	}
+	if err := c.sftpClient.MkdirAll(path.Dir(remotePath)); err != nil {
+		return nil, fmt.Errorf("创建远程目录失败 %s: %v", path.Dir(remotePath), err)
+	}
+	file, err := c.sftpClient.Create(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+	return file, nil
+}
+
+// List 实现 Backend：列出远程目录下的直接子项。
+func (c *SSHClient) List(remotePath string) ([]BackendFileInfo, error) {
+	if !c.connected {
+		return nil, fmt.Errorf("ssh未连接")
+	}
+	entries, err := c.sftpClient.ReadDir(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程目录失败 %s: %v", remotePath, err)
+	}
+
+	infos := make([]BackendFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, BackendFileInfo{
+			Path:    path.Join(remotePath, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+			IsDir:   entry.IsDir(),
+		})
+	}
+	return infos, nil
+}
+
+// Remove 实现 Backend：删除远程文件。
+func (c *SSHClient) Remove(remotePath string) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+	if err := c.sftpClient.Remove(remotePath); err != nil {
+		return fmt.Errorf("删除远程文件失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Mkdir 实现 Backend：递归创建远程目录。
+func (c *SSHClient) Mkdir(remotePath string) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+	if err := c.sftpClient.MkdirAll(remotePath); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Chmod 实现 backendMetadataSetter：流式加解密路径（encrypted_transfer.go）
+// 写完内容后用它回填原始文件的权限，其余 Backend 调用方不依赖这个方法。
+func (c *SSHClient) Chmod(remotePath string, mode os.FileMode) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+	if err := c.sftpClient.Chmod(remotePath, mode); err != nil {
+		return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Chtimes 实现 backendMetadataSetter：回填原始文件的修改时间。
+func (c *SSHClient) Chtimes(remotePath string, modTime time.Time) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+	if err := c.sftpClient.Chtimes(remotePath, modTime, modTime); err != nil {
+		return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package remote
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// TestNewBackendSchemeDispatch 覆盖 NewBackend 按 URL scheme 选择具体实现
+// 的分支，包括新增的 s3s/webdavs TLS 变体；S3/WebDAV 的构造函数只记录
+// 连接参数、不发起网络请求，因此这里不需要真实的远程服务器。
+func TestNewBackendSchemeDispatch(t *testing.T) {
+	config := &RemoteConfig{
+		SSH: &SSHConfig{Host: "example.invalid", Port: 1234, Username: "u", Password: "p"},
+	}
+
+	cases := []struct {
+		name    string
+		rawURL  string
+		wantErr bool
+	}{
+		{"sftp无SSHClient应报错", "sftp://whatever", true},
+		{"空scheme无SSHClient应报错", "", true},
+		{"file后端", "file:///tmp/synccli-backend-test", false},
+		{"webdav后端", "webdav://ignored/bucket", false},
+		{"webdavs后端", "webdavs://ignored/bucket", false},
+		{"s3后端", "s3:///my-bucket/prefix", false},
+		{"s3s后端", "s3s:///my-bucket/prefix", false},
+		{"不支持的scheme应报错", "ftp://whatever", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, err := NewBackend(tc.rawURL, config, nil)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("%s: 期望报错，实际没有", tc.rawURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: 未预期的错误: %v", tc.rawURL, err)
+			}
+			if backend == nil {
+				t.Fatalf("%s: 期望返回非 nil 的 Backend", tc.rawURL)
+			}
+		})
+	}
+}
+
+// TestNewBackendSFTPSchemeReusesSSHClient 验证 sftp:// scheme 在有
+// SSHClient 时直接把它当作 Backend 返回，而不是另起一个实现。
+func TestNewBackendSFTPSchemeReusesSSHClient(t *testing.T) {
+	sshClient := &SSHClient{}
+	backend, err := NewBackend("sftp://whatever", &RemoteConfig{}, sshClient)
+	if err != nil {
+		t.Fatalf("未预期的错误: %v", err)
+	}
+	if backend != Backend(sshClient) {
+		t.Fatal("sftp scheme应当直接返回传入的 sshClient")
+	}
+}
+
+// TestLocalBackendRoundTrip 覆盖 LocalBackend 的 Create/Open/Stat/List/
+// Remove/Mkdir 基本语义。
+func TestLocalBackendRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	backend := NewLocalBackend(root)
+
+	writer, err := backend.Create("dir/file.txt")
+	if err != nil {
+		t.Fatalf("Create失败: %v", err)
+	}
+	if _, err := io.WriteString(writer, "hello"); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+
+	info, err := backend.Stat("dir/file.txt")
+	if err != nil {
+		t.Fatalf("Stat失败: %v", err)
+	}
+	if info.Size != int64(len("hello")) {
+		t.Fatalf("Size = %d, want %d", info.Size, len("hello"))
+	}
+
+	reader, err := backend.Open("dir/file.txt")
+	if err != nil {
+		t.Fatalf("Open失败: %v", err)
+	}
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if string(content) != "hello" {
+		t.Fatalf("content = %q, want %q", content, "hello")
+	}
+
+	entries, err := backend.List("dir")
+	if err != nil {
+		t.Fatalf("List失败: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != filepath.Join("dir", "file.txt") {
+		t.Fatalf("List = %+v, want一个 dir/file.txt 条目", entries)
+	}
+
+	if err := backend.Remove("dir/file.txt"); err != nil {
+		t.Fatalf("Remove失败: %v", err)
+	}
+	if _, err := backend.Stat("dir/file.txt"); err == nil {
+		t.Fatal("Remove后Stat应当报错")
+	}
+
+	if err := backend.Mkdir("newdir/nested"); err != nil {
+		t.Fatalf("Mkdir失败: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "newdir", "nested")); err != nil || !info.IsDir() {
+		t.Fatalf("Mkdir之后目录应当存在: %v", err)
+	}
+}
+
+// TestWalkBackendRecursesNestedDirectories 验证 walkBackend 能递归遍历
+// 多层目录并只返回文件、不返回目录本身。
+func TestWalkBackendRecursesNestedDirectories(t *testing.T) {
+	root := t.TempDir()
+	backend := NewLocalBackend(root)
+
+	files := []string{"a.txt", "sub/b.txt", "sub/nested/c.txt"}
+	for _, f := range files {
+		w, err := backend.Create(f)
+		if err != nil {
+			t.Fatalf("Create(%s)失败: %v", f, err)
+		}
+		if _, err := io.WriteString(w, f); err != nil {
+			t.Fatalf("写入(%s)失败: %v", f, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close(%s)失败: %v", f, err)
+		}
+	}
+
+	entries, err := walkBackend(backend, "")
+	if err != nil {
+		t.Fatalf("walkBackend失败: %v", err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		if e.IsDir {
+			t.Fatalf("walkBackend不应当返回目录条目: %+v", e)
+		}
+		got = append(got, filepath.ToSlash(e.Path))
+	}
+	sort.Strings(got)
+
+	want := []string{"a.txt", "sub/b.txt", "sub/nested/c.txt"}
+	sort.Strings(want)
+
+	if len(got) != len(want) {
+		t.Fatalf("walkBackend条目数 = %d, want %d (got=%v)", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("walkBackend结果 = %v, want %v", got, want)
+		}
+	}
+}
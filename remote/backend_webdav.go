@@ -0,0 +1,119 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/studio-b12/gowebdav"
+)
+
+// WebDAVBackend 与 WebDAVTransport 同样基于 gowebdav.Client，但直接
+// 暴露 gowebdav 原生支持的 ReadStream/WriteStream 句柄语义，而不是像
+// WebDAVTransport.Put/Get 那样只接受本地路径。
+type WebDAVBackend struct {
+	config *SSHConfig
+	client *gowebdav.Client
+}
+
+// webdavURLScheme 按 secure 返回 WebDAV 后端应当使用的 URL scheme，
+// WebDAVBackend 与 WebDAVTransport 共用，避免两处各自拼一份 if/else。
+func webdavURLScheme(secure bool) string {
+	if secure {
+		return "https"
+	}
+	return "http"
+}
+
+// NewWebDAVBackend 创建一个 WebDAV 后端，复用 SSHConfig 的
+// Host/Port/Username/Password 作为连接参数；secure 为 true 时使用
+// https://（对应 ProtocolWebDAVS/backendSchemeWebDAVSecure）。
+func NewWebDAVBackend(config *SSHConfig, secure bool) *WebDAVBackend {
+	baseURL := fmt.Sprintf("%s://%s:%d", webdavURLScheme(secure), config.Host, config.Port)
+	return &WebDAVBackend{
+		config: config,
+		client: gowebdav.NewClient(baseURL, config.Username, config.Password),
+	}
+}
+
+func (b *WebDAVBackend) Stat(p string) (BackendFileInfo, error) {
+	info, err := b.client.Stat(p)
+	if err != nil {
+		return BackendFileInfo{}, fmt.Errorf("获取WebDAV文件信息失败 %s: %v", p, err)
+	}
+	return BackendFileInfo{Path: p, Size: info.Size(), ModTime: info.ModTime(), IsDir: info.IsDir()}, nil
+}
+
+func (b *WebDAVBackend) Open(p string) (io.ReadCloser, error) {
+	stream, err := b.client.ReadStream(p)
+	if err != nil {
+		return nil, fmt.Errorf("打开WebDAV文件失败 %s: %v", p, err)
+	}
+	return stream, nil
+}
+
+// webdavWriter 把 gowebdav 的 WriteStream（一次性接收完整 io.Reader）
+// 适配成 Backend.Create 要求的增量 io.WriteCloser：写入内容先缓冲在
+// 管道里，Close 时等待 WriteStream 协程把管道另一端读完。
+type webdavWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *webdavWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *webdavWriter) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func (b *WebDAVBackend) Create(p string) (io.WriteCloser, error) {
+	if err := b.client.MkdirAll(path.Dir(p), 0755); err != nil {
+		return nil, fmt.Errorf("创建WebDAV目录失败 %s: %v", path.Dir(p), err)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- b.client.WriteStream(p, pr, 0644)
+		pr.Close()
+	}()
+
+	return &webdavWriter{pw: pw, done: done}, nil
+}
+
+func (b *WebDAVBackend) List(p string) ([]BackendFileInfo, error) {
+	entries, err := b.client.ReadDir(p)
+	if err != nil {
+		return nil, fmt.Errorf("列出WebDAV目录失败 %s: %v", p, err)
+	}
+
+	infos := make([]BackendFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, BackendFileInfo{
+			Path:    path.Join(p, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+			IsDir:   entry.IsDir(),
+		})
+	}
+	return infos, nil
+}
+
+func (b *WebDAVBackend) Remove(p string) error {
+	if err := b.client.Remove(p); err != nil {
+		return fmt.Errorf("删除WebDAV文件失败 %s: %v", p, err)
+	}
+	return nil
+}
+
+func (b *WebDAVBackend) Mkdir(p string) error {
+	if err := b.client.MkdirAll(p, 0755); err != nil {
+		return fmt.Errorf("创建WebDAV目录失败 %s: %v", p, err)
+	}
+	return nil
+}
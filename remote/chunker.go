@@ -0,0 +1,84 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// CDC（内容定义分块）参数：目标平均分块大小约 1 MiB，下限 512 KiB 避免
+// 分块过碎，上限 4 MiB 避免单个分块失去增量传输的意义。
+const (
+	cdcMinChunkSize = 512 * 1024
+	cdcMaxChunkSize = 4 * 1024 * 1024
+	cdcMaskBits     = 20 // 2^20 = 1 MiB 的期望平均分块大小
+	cdcMask         = (1 << cdcMaskBits) - 1
+)
+
+// gearTable 是 Gear hash 滚动指纹使用的 256 项查找表，由固定种子的线性
+// 同余生成器产生，保证同一份数据在任意时刻分块结果都一致可复现，
+// 无需引入额外的第三方分块库。
+var gearTable = buildGearTable()
+
+func buildGearTable() [256]uint64 {
+	var table [256]uint64
+	seed := uint64(0x9E3779B97F4A7C15)
+	for i := range table {
+		seed = seed*6364136223846793005 + 1442695040888963407
+		table[i] = seed
+	}
+	return table
+}
+
+// Chunk 是一次内容定义分块产生的一个分块：在源数据中的偏移、长度、
+// 内容的 SHA-256 强哈希，以及分块本身的数据。
+type Chunk struct {
+	Offset int64
+	Length int
+	Hash   string
+	Data   []byte
+}
+
+// chunkData 对内存中的字节切片执行 Gear-hash 内容定义分块：维护一个
+// 滚动指纹，当累计长度达到下限后，一旦指纹的低 cdcMaskBits 位全为零，
+// 就在当前位置切出一个分块边界；达到上限时无条件切分，避免退化出
+// 巨大分块。这与 restic/chunker 等备份去重工具使用的思路一致 ——
+// 文件中一段未变化的内容在编辑前后会被切出相同的分块，从而只需要
+// 重传发生变化的部分。
+func chunkData(data []byte) []Chunk {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var chunks []Chunk
+	start := 0
+	var fingerprint uint64
+
+	for i := 0; i < len(data); i++ {
+		fingerprint = (fingerprint << 1) + gearTable[data[i]]
+		size := i - start + 1
+
+		if size >= cdcMaxChunkSize || (size >= cdcMinChunkSize && fingerprint&cdcMask == 0) {
+			chunks = append(chunks, newChunk(data, start, i+1))
+			start = i + 1
+			fingerprint = 0
+		}
+	}
+
+	if start < len(data) {
+		chunks = append(chunks, newChunk(data, start, len(data)))
+	}
+
+	return chunks
+}
+
+// newChunk 计算 data[start:end] 的强哈希并打包成一个 Chunk。
+func newChunk(data []byte, start, end int) Chunk {
+	block := data[start:end]
+	sum := sha256.Sum256(block)
+	return Chunk{
+		Offset: int64(start),
+		Length: len(block),
+		Hash:   hex.EncodeToString(sum[:]),
+		Data:   block,
+	}
+}
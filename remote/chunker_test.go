@@ -0,0 +1,99 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestChunkDataEmptyInput(t *testing.T) {
+	if chunks := chunkData(nil); chunks != nil {
+		t.Fatalf("空输入应当返回nil，got %d个分块", len(chunks))
+	}
+}
+
+// TestChunkDataReconstructsOriginal 验证所有分块首尾相接、互不重叠地覆盖
+// 了整个输入，偏移量与长度也与实际切出的数据一致。
+func TestChunkDataReconstructsOriginal(t *testing.T) {
+	data := make([]byte, 8*cdcMaxChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("生成随机数据失败: %v", err)
+	}
+
+	chunks := chunkData(data)
+	if len(chunks) == 0 {
+		t.Fatal("非空输入应当至少切出一个分块")
+	}
+
+	var reconstructed bytes.Buffer
+	for i, c := range chunks {
+		if c.Offset != int64(reconstructed.Len()) {
+			t.Fatalf("第%d个分块的Offset=%d，期望紧接在前一个分块之后的%d", i, c.Offset, reconstructed.Len())
+		}
+		if c.Length != len(c.Data) {
+			t.Fatalf("第%d个分块Length=%d与len(Data)=%d不一致", i, c.Length, len(c.Data))
+		}
+		if i < len(chunks)-1 && c.Length > cdcMaxChunkSize {
+			t.Fatalf("第%d个分块大小%d超过cdcMaxChunkSize", i, c.Length)
+		}
+		reconstructed.Write(c.Data)
+	}
+
+	if !bytes.Equal(reconstructed.Bytes(), data) {
+		t.Fatal("按分块顺序拼接的数据与原始数据不一致")
+	}
+}
+
+func TestChunkDataDeterministic(t *testing.T) {
+	data := make([]byte, 4*cdcMaxChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("生成随机数据失败: %v", err)
+	}
+
+	first := chunkData(data)
+	second := chunkData(data)
+
+	if len(first) != len(second) {
+		t.Fatalf("同一份数据两次分块结果的分块数不同: %d vs %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].Hash != second[i].Hash || first[i].Offset != second[i].Offset {
+			t.Fatalf("第%d个分块在两次运行间不一致", i)
+		}
+	}
+}
+
+// TestChunkDataStableUnderInsertion 验证 CDC 的核心属性：在数据中段插入
+// 几个字节后，插入点之前与（在内容重新对齐之后）插入点之后足够远的分块
+// 哈希应当保持不变，不会像固定大小分块那样让插入点之后的全部分块错位。
+func TestChunkDataStableUnderInsertion(t *testing.T) {
+	original := make([]byte, 6*cdcMaxChunkSize)
+	if _, err := rand.Read(original); err != nil {
+		t.Fatalf("生成随机数据失败: %v", err)
+	}
+
+	modified := make([]byte, 0, len(original)+8)
+	insertAt := len(original) / 2
+	modified = append(modified, original[:insertAt]...)
+	modified = append(modified, []byte("INSERTED")...)
+	modified = append(modified, original[insertAt:]...)
+
+	before := chunkData(original)
+	after := chunkData(modified)
+
+	beforeHashes := make(map[string]bool, len(before))
+	for _, c := range before {
+		beforeHashes[c.Hash] = true
+	}
+
+	shared := 0
+	for _, c := range after {
+		if beforeHashes[c.Hash] {
+			shared++
+		}
+	}
+
+	if shared == 0 {
+		t.Fatal("插入几个字节后应当仍有分块的哈希与原始分块相同，但一个都没有")
+	}
+}
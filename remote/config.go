@@ -1,162 +1,150 @@
 package remote
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 // 远程同步配置
 type RemoteConfig struct {
-	Name            string     `json:"name"`
-	SSH             *SSHConfig `json:"ssh"`
-	RemoteBase      string     `json:"remoteBase"`
-	Compression     bool       `json:"compression"`
-	Encryption      bool       `json:"encryption"`
-	Incremental     bool       `json:"incremental"`
-	KnownHostsFile  string     `json:"knownHostsFile"`
-	StrictHostCheck bool       `json:"strictHostCheck"`
-	ExcludeList     []string   `json:"excludeList"`
+	Name            string     `json:"name" yaml:"name"`
+	Protocol        string     `json:"protocol" yaml:"protocol"` // 传输协议: ssh(默认)|ftp|ftps|webdav
+	SSH             *SSHConfig `json:"ssh" yaml:"ssh"`
+	RemoteBase      string     `json:"remoteBase" yaml:"remoteBase"`
+	Compression     bool       `json:"compression" yaml:"compression"`
+	Encryption      bool       `json:"encryption" yaml:"encryption"`
+	Incremental     bool       `json:"incremental" yaml:"incremental"`
+	KnownHostsFile  string     `json:"knownHostsFile" yaml:"knownHostsFile"`
+	StrictHostCheck bool       `json:"strictHostCheck" yaml:"strictHostCheck"`
+	ExcludeList     []string   `json:"excludeList" yaml:"excludeList"`
+	// Extends 是这个配置继承的模板名（参见 config_template.go 的
+	// Templates/ResolveConfig），为空表示不继承。
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
+	// Target 为非空时覆盖 Protocol 驱动的 Transport 选型：按 URL scheme
+	// （sftp/s3/s3s/webdav/webdavs/file，参见 backend.go 的 NewBackend）
+	// 选择一个 Backend 来承载实际的文件传输与远程目录扫描，连接参数仍然
+	// 取自 SSH。这是让 RemoteSyncEngine 能对接 S3 兼容对象存储、WebDAV
+	// 共享目录这类没有会话/目录语义的后端的入口——Transport 抽象假设了
+	// "目录遍历+会话"，对象存储并不满足。为空时保持原有的 Protocol 行为
+	// 不变。
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
-// 配置管理器
+// 配置管理器：具体的持久化格式由 ConfigStore（参见 config_store.go）负责，
+// ConfigManager 自身只管校验、默认值填充这类与存储格式无关的业务逻辑。
+// watchMu 及其后的字段只在调用过 Subscribe（参见 config_watch.go）之后才
+// 会被使用，用于支持配置文件的热加载。
 type ConfigManager struct {
-	configDir  string
-	configFile string
-	configs    map[string]*RemoteConfig
+	configDir string
+	store     ConfigStore
+
+	watchMu      sync.RWMutex
+	watcher      *fsnotify.Watcher
+	watchDone    chan struct{}
+	lastSnapshot map[string]*RemoteConfig
+	subscribers  []chan ConfigEvent
 }
 
-// 创建新的配置管理器
-func NewConfigManager() (*ConfigManager, error) {
+// 创建新的配置管理器。opt 为零值时，若 ~/.synccli/backend.json 记录过之前
+// 通过 `config backend set` 选择的后端则沿用该选择，否则默认使用
+// StoreTypeJSON（即原有的 ~/.synccli/remote_configs.json）。
+func NewConfigManager(opt StoreOption) (*ConfigManager, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("Errorf: %v", err)
 	}
 
-	configDir := filepath.Join(homeDir, ".synccli")
-	configFile := filepath.Join(configDir, "remote_configs.json")
+	configDir := opt.ConfigDir
+	if configDir == "" {
+		configDir = filepath.Join(homeDir, ".synccli")
+	}
 
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return nil, fmt.Errorf("Errorf: %v", err)
 	}
 
-	cm := &ConfigManager{
-		configDir:  configDir,
-		configFile: configFile,
-		configs:    make(map[string]*RemoteConfig),
+	store, err := newConfigStore(configDir, opt)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := cm.LoadConfigs(); err != nil {
-		if !os.IsNotExist(err) {
+	cm := &ConfigManager{configDir: configDir, store: store}
+
+	if _, err := cm.store.Load(); err != nil {
+		if !os.IsNotExist(err) && err != ErrVaultLocked {
 			return nil, err
 		}
 	}
 	return cm, nil
 }
 
-// 加载配置文件
+// LoadConfigs 重新从存储读取一次配置，主要用于在 Unlock 之后刷新状态。
 func (cm *ConfigManager) LoadConfigs() error {
-	data, err := os.ReadFile(cm.configFile)
-	if err != nil {
-		return err
-	}
-
-	return json.Unmarshal(data, &cm.configs)
+	_, err := cm.store.Load()
+	return err
 }
 
-// 保存配置文件
+// SaveConfigs 把当前全部配置整体写回存储；对 JSON 文件后端而言，这也是
+// Unlock 之后触发明文到加密信封迁移的入口。
 func (cm *ConfigManager) SaveConfigs() error {
-	data, err := json.MarshalIndent(cm.configs, "", " ")
+	configs, err := cm.store.Load()
 	if err != nil {
-		return fmt.Errorf("Errorf: %v", err)
+		return err
 	}
-	return os.WriteFile(cm.configFile, data, 0600)
+	return cm.store.Save(configs)
 }
 
 // 添加远程配置
 func (cm *ConfigManager) AddConfig(config *RemoteConfig) error {
-	if config.Name == "" {
-		return fmt.Errorf("the name is null")
-	}
-	if config.SSH == nil {
-		return fmt.Errorf("ssh is null")
-	}
-	if config.SSH.Host == "" {
-		return fmt.Errorf("the host is null")
-	}
-	if config.SSH.Username == "" {
-		return fmt.Errorf("the name is null")
-	}
 	if config.RemoteBase == "" {
 		config.RemoteBase = "/tmp/synccli"
 	}
+	if err := cm.ValidateConfig(config); err != nil {
+		return err
+	}
 
-	cm.configs[config.Name] = config
-	return cm.SaveConfigs()
+	return cm.store.Put(config.Name, config)
 }
 
-// 获取指定名称的配置
+// 获取指定名称的配置。若它声明了 Extends，会先与对应模板深度合并，再对
+// 关键字符串字段做环境变量插值，细节见 config_template.go 的 ResolveConfig。
 func (cm *ConfigManager) GetConfig(name string) (*RemoteConfig, error) {
-	config, err := cm.configs[name]
-	if !err {
-		return nil, fmt.Errorf("this is null: %s", name)
-	}
-	return config, nil
+	return cm.ResolveConfig(name)
 }
 
-// 列出所有配置
+// 列出所有配置。存储出错（例如仓库处于锁定状态）时返回空表而不是报错，
+// 方便只读展示场景不必特殊处理各种存储后端各自的错误类型。
 func (cm *ConfigManager) ListConfigs() map[string]*RemoteConfig {
-	return cm.configs
+	configs, err := cm.store.List()
+	if err != nil {
+		return map[string]*RemoteConfig{}
+	}
+	return configs
 }
 
 // 删除指定配置
 func (cm *ConfigManager) RemoveConfig(name string) error {
-	if _, exists := cm.configs[name]; !exists {
-		return fmt.Errorf("this is null: %s", name)
-	}
-
-	delete(cm.configs, name)
-	return cm.SaveConfigs()
+	return cm.store.Delete(name)
 }
 
 // 更新配置
 func (cm *ConfigManager) UpdateConfig(name string, config *RemoteConfig) error {
-	if _, exists := cm.configs[name]; !exists {
+	if _, err := cm.store.Get(name); err != nil {
 		return fmt.Errorf("this is null: %s", name)
 	}
 	config.Name = name
-	cm.configs[name] = config
-	return cm.SaveConfigs()
-}
-
-// 验证配置
-func (cm *ConfigManager) ValidateConfig(config *RemoteConfig) error {
-	if config.SSH == nil {
-		return fmt.Errorf("ssh is null")
-	}
-	if config.SSH.Host == "" {
-		return fmt.Errorf("the host is null")
-	}
-	if config.SSH.Username == "" {
-		return fmt.Errorf("the name is null")
-	}
-	if config.SSH.Password == "" && config.SSH.KeyFile == "" {
-		return fmt.Errorf("the password or key is null")
-	}
-
-	if config.SSH.KeyFile != "" {
-		if _, err := os.Stat(config.SSH.KeyFile); os.IsNotExist(err) {
-			return fmt.Errorf("the key is null: %s", config.SSH.KeyFile)
-		}
-	}
-	return nil
+	return cm.store.Put(name, config)
 }
 
 // 默认配置模板
 func (cm *ConfigManager) CreateDefaultConfig(name, host, username string) *RemoteConfig {
 	return &RemoteConfig{
-		Name: name,
+		Name:     name,
+		Protocol: ProtocolSSH,
 		SSH: &SSHConfig{
 			Host:     host,
 			Port:     22,
@@ -0,0 +1,319 @@
+package remote
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportOptions 控制 ExportBundle 打包哪些附加内容、是否脱敏。
+type ExportOptions struct {
+	// RedactSecrets 为 true 时清空每个配置的 SSH.Password 字段；私钥文件
+	// 是否一并打包由 IncludeKeyFiles 单独控制，口令和私钥是两回事。
+	RedactSecrets bool
+	// IncludeKeyFiles 为 true 时把每个配置 SSH.KeyFile 指向的私钥文件内
+	// 容一并打包进 bundle，供 ImportBundle 还原到同一路径。
+	IncludeKeyFiles bool
+	// IncludeKnownHosts 为 true 时把每个配置 KnownHostsFile 指向的文件
+	// 内容一并打包进 bundle。
+	IncludeKnownHosts bool
+	// SigningKey 非空时对 manifest 做 Ed25519 签名并写入 bundle，供接收
+	// 方用对应公钥验证来源；留空则导出不签名的 bundle。
+	SigningKey ed25519.PrivateKey
+}
+
+// ImportConflictPolicy 决定导入时遇到本地已存在同名配置该怎么处理。
+type ImportConflictPolicy string
+
+const (
+	ImportSkip      ImportConflictPolicy = "skip"
+	ImportOverwrite ImportConflictPolicy = "overwrite"
+	ImportRename    ImportConflictPolicy = "rename"
+)
+
+// ImportOptions 控制 ImportBundle 的冲突处理与签名校验。
+type ImportOptions struct {
+	// Conflict 为空时按 ImportSkip 处理。
+	Conflict ImportConflictPolicy
+	// VerifyKey 非空时要求 bundle 携带能用该公钥验证通过的签名，否则拒
+	// 绝导入；留空则跳过签名校验。
+	VerifyKey ed25519.PublicKey
+}
+
+// configBundleManifest 是 tar.gz 里 manifest.json 的内容：被导出的配置表，
+// 以 names 声明的原始配置名为 key 额外附带其引用的密钥/known_hosts 文件
+// 内容（如果打包了的话）。
+type configBundleManifest struct {
+	Configs    map[string]*RemoteConfig `json:"configs"`
+	KeyFiles   map[string][]byte        `json:"keyFiles,omitempty"`
+	KnownHosts map[string][]byte        `json:"knownHosts,omitempty"`
+}
+
+const (
+	bundleManifestName  = "manifest.json"
+	bundleSignatureName = "manifest.sig"
+)
+
+// ExportBundle 把 names 指定的配置（names 为空时导出全部）打包成一个 tar.gz
+// 写入 w，可选附带它们引用的私钥/known_hosts 文件内容与 Ed25519 签名，让
+// 团队之间可以分享预置的部署目标而不用裸发 JSON。
+func (cm *ConfigManager) ExportBundle(names []string, w io.Writer, opts ExportOptions) error {
+	all, err := cm.store.List()
+	if err != nil {
+		return fmt.Errorf("读取配置失败: %v", err)
+	}
+
+	selected := make(map[string]*RemoteConfig)
+	if len(names) == 0 {
+		for name, config := range all {
+			selected[name] = config
+		}
+	} else {
+		for _, name := range names {
+			config, ok := all[name]
+			if !ok {
+				return fmt.Errorf("this is null: %s", name)
+			}
+			selected[name] = config
+		}
+	}
+
+	manifest := configBundleManifest{Configs: make(map[string]*RemoteConfig, len(selected))}
+	if opts.IncludeKeyFiles {
+		manifest.KeyFiles = make(map[string][]byte)
+	}
+	if opts.IncludeKnownHosts {
+		manifest.KnownHosts = make(map[string][]byte)
+	}
+
+	for name, config := range selected {
+		entry := *config
+		if config.SSH != nil {
+			sshCopy := *config.SSH
+			entry.SSH = &sshCopy
+		}
+
+		if opts.RedactSecrets && entry.SSH != nil {
+			entry.SSH.Password = ""
+		}
+
+		if opts.IncludeKeyFiles && entry.SSH != nil && entry.SSH.KeyFile != "" {
+			data, err := os.ReadFile(entry.SSH.KeyFile)
+			if err != nil {
+				return fmt.Errorf("读取密钥文件失败 %s: %v", entry.SSH.KeyFile, err)
+			}
+			manifest.KeyFiles[name] = data
+		}
+		if opts.IncludeKnownHosts && entry.KnownHostsFile != "" {
+			data, err := os.ReadFile(entry.KnownHostsFile)
+			if err != nil {
+				return fmt.Errorf("读取known_hosts文件失败 %s: %v", entry.KnownHostsFile, err)
+			}
+			manifest.KnownHosts[name] = data
+		}
+
+		manifest.Configs[name] = &entry
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return fmt.Errorf("序列化bundle失败: %v", err)
+	}
+
+	var signature []byte
+	if opts.SigningKey != nil {
+		signature = ed25519.Sign(opts.SigningKey, manifestData)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeBundleFile(tw, bundleManifestName, manifestData); err != nil {
+		return err
+	}
+	if signature != nil {
+		encoded := []byte(base64.StdEncoding.EncodeToString(signature))
+		if err := writeBundleFile(tw, bundleSignatureName, encoded); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("写入bundle失败: %v", err)
+	}
+	return gz.Close()
+}
+
+func writeBundleFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("写入bundle失败: %v", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("写入bundle失败: %v", err)
+	}
+	return nil
+}
+
+// ImportBundle 读取 ExportBundle 产出的 tar.gz：校验签名（如果 opts.VerifyKey
+// 非空），再按 opts.Conflict 策略把 manifest 里的配置写入当前存储后端，
+// 附带的私钥/known_hosts 文件内容会被还原到配置自身引用的路径；该路径必须
+// 位于配置目录之下，否则还原失败，ImportBundle 返回错误。返回实际写入的
+// 配置名列表。
+func (cm *ConfigManager) ImportBundle(r io.Reader, opts ImportOptions) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("解压bundle失败: %v", err)
+	}
+	defer gz.Close()
+
+	manifestData, signatureData, err := readBundleFiles(tar.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	if manifestData == nil {
+		return nil, fmt.Errorf("bundle里缺少manifest")
+	}
+
+	if opts.VerifyKey != nil {
+		if signatureData == nil {
+			return nil, fmt.Errorf("bundle未签名，无法校验来源")
+		}
+		signature, err := base64.StdEncoding.DecodeString(string(signatureData))
+		if err != nil {
+			return nil, fmt.Errorf("解析签名失败: %v", err)
+		}
+		if !ed25519.Verify(opts.VerifyKey, manifestData, signature) {
+			return nil, fmt.Errorf("签名校验失败，拒绝导入")
+		}
+	}
+
+	var manifest configBundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("解析manifest失败: %v", err)
+	}
+
+	existing, err := cm.store.List()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("读取现有配置失败: %v", err)
+		}
+		existing = make(map[string]*RemoteConfig)
+	}
+
+	conflict := opts.Conflict
+	if conflict == "" {
+		conflict = ImportSkip
+	}
+
+	var imported []string
+	for name, config := range manifest.Configs {
+		targetName := name
+		if _, exists := existing[name]; exists {
+			switch conflict {
+			case ImportSkip:
+				continue
+			case ImportRename:
+				targetName = uniqueConfigName(existing, name)
+			case ImportOverwrite:
+				// 沿用原名，Put 会直接覆盖。
+			default:
+				return nil, fmt.Errorf("未知的冲突处理策略: %s", conflict)
+			}
+		}
+
+		config.Name = targetName
+		if err := cm.store.Put(targetName, config); err != nil {
+			return nil, fmt.Errorf("写入配置失败 %s: %v", targetName, err)
+		}
+		existing[targetName] = config
+
+		keyFilePath := ""
+		if config.SSH != nil {
+			keyFilePath = config.SSH.KeyFile
+		}
+		if err := restoreBundleFile(cm.configDir, keyFilePath, manifest.KeyFiles[name]); err != nil {
+			return nil, err
+		}
+		if err := restoreBundleFile(cm.configDir, config.KnownHostsFile, manifest.KnownHosts[name]); err != nil {
+			return nil, err
+		}
+
+		imported = append(imported, targetName)
+	}
+
+	return imported, nil
+}
+
+func readBundleFiles(tr *tar.Reader) (manifestData, signatureData []byte, err error) {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return manifestData, signatureData, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取bundle失败: %v", err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取bundle失败: %v", err)
+		}
+
+		switch hdr.Name {
+		case bundleManifestName:
+			manifestData = data
+		case bundleSignatureName:
+			signatureData = data
+		}
+	}
+}
+
+// restoreBundleFile 把 data 写到 path（没有打包这个文件，或者配置压根没
+// 引用它时 data/path 为空，直接跳过）。path 来自 bundle 里的 manifest，
+// 是不受信任的输入，因此要求它解析后落在 root（configDir）之下，防止恶
+// 意构造的 bundle 借 KeyFile/KnownHostsFile 把任意内容写到 configDir 之
+// 外（例如 ~/.ssh/authorized_keys、~/.bashrc）。
+func restoreBundleFile(root, path string, data []byte) error {
+	if path == "" || data == nil {
+		return nil
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("还原文件失败 %s: %v", path, err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("还原文件失败 %s: %v", path, err)
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("拒绝还原文件 %s: 路径不在配置目录 %s 之内", path, absRoot)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(absPath), 0700); err != nil {
+		return fmt.Errorf("还原文件失败 %s: %v", path, err)
+	}
+	if err := os.WriteFile(absPath, data, 0600); err != nil {
+		return fmt.Errorf("还原文件失败 %s: %v", path, err)
+	}
+	return nil
+}
+
+// uniqueConfigName 在 existing 里找一个不冲突的名字，形如 name-2、name-3。
+func uniqueConfigName(existing map[string]*RemoteConfig, name string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if _, exists := existing[candidate]; !exists {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+)
+
+func newTestConfigManager(t *testing.T) *ConfigManager {
+	t.Helper()
+	cm, err := NewConfigManager(StoreOption{Type: StoreTypeJSON, ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewConfigManager失败: %v", err)
+	}
+	return cm
+}
+
+func mustAddConfig(t *testing.T, cm *ConfigManager, name string) *RemoteConfig {
+	t.Helper()
+	config := &RemoteConfig{
+		Name:       name,
+		Protocol:   ProtocolSSH,
+		RemoteBase: "/tmp/synccli",
+		SSH: &SSHConfig{
+			Host:     "example.com",
+			Port:     22,
+			Username: "deploy",
+			Password: "s3cret",
+			Timeout:  30,
+		},
+	}
+	if err := cm.AddConfig(config); err != nil {
+		t.Fatalf("AddConfig失败: %v", err)
+	}
+	return config
+}
+
+func TestExportImportBundleRoundTrip(t *testing.T) {
+	src := newTestConfigManager(t)
+	mustAddConfig(t, src, "prod")
+
+	var buf bytes.Buffer
+	if err := src.ExportBundle(nil, &buf, ExportOptions{}); err != nil {
+		t.Fatalf("ExportBundle失败: %v", err)
+	}
+
+	dst := newTestConfigManager(t)
+	imported, err := dst.ImportBundle(&buf, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportBundle失败: %v", err)
+	}
+	if len(imported) != 1 || imported[0] != "prod" {
+		t.Fatalf("期望导入[prod]，got %v", imported)
+	}
+
+	got, err := dst.GetConfig("prod")
+	if err != nil {
+		t.Fatalf("导入后GetConfig失败: %v", err)
+	}
+	if got.SSH.Host != "example.com" || got.SSH.Password != "s3cret" {
+		t.Fatalf("导入后的配置内容不符: %+v", got.SSH)
+	}
+}
+
+func TestExportBundleRedactSecrets(t *testing.T) {
+	src := newTestConfigManager(t)
+	mustAddConfig(t, src, "prod")
+
+	var buf bytes.Buffer
+	if err := src.ExportBundle(nil, &buf, ExportOptions{RedactSecrets: true}); err != nil {
+		t.Fatalf("ExportBundle失败: %v", err)
+	}
+
+	dst := newTestConfigManager(t)
+	if _, err := dst.ImportBundle(&buf, ImportOptions{}); err != nil {
+		t.Fatalf("ImportBundle失败: %v", err)
+	}
+
+	got, err := dst.GetConfig("prod")
+	if err != nil {
+		t.Fatalf("GetConfig失败: %v", err)
+	}
+	if got.SSH.Password != "" {
+		t.Fatalf("RedactSecrets为true时导出的bundle不应携带明文密码，got %q", got.SSH.Password)
+	}
+}
+
+func TestImportBundleSignatureVerification(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("生成Ed25519密钥失败: %v", err)
+	}
+
+	src := newTestConfigManager(t)
+	mustAddConfig(t, src, "prod")
+
+	var signed bytes.Buffer
+	if err := src.ExportBundle(nil, &signed, ExportOptions{SigningKey: priv}); err != nil {
+		t.Fatalf("ExportBundle失败: %v", err)
+	}
+
+	dst := newTestConfigManager(t)
+	if _, err := dst.ImportBundle(bytes.NewReader(signed.Bytes()), ImportOptions{VerifyKey: pub}); err != nil {
+		t.Fatalf("用正确的公钥校验签名应当成功，got: %v", err)
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("生成Ed25519密钥失败: %v", err)
+	}
+	dst2 := newTestConfigManager(t)
+	if _, err := dst2.ImportBundle(bytes.NewReader(signed.Bytes()), ImportOptions{VerifyKey: otherPub}); err == nil {
+		t.Fatal("用错误的公钥校验签名应当失败，但ImportBundle返回了nil")
+	}
+
+	var unsigned bytes.Buffer
+	if err := src.ExportBundle(nil, &unsigned, ExportOptions{}); err != nil {
+		t.Fatalf("ExportBundle失败: %v", err)
+	}
+	dst3 := newTestConfigManager(t)
+	if _, err := dst3.ImportBundle(&unsigned, ImportOptions{VerifyKey: pub}); err == nil {
+		t.Fatal("要求签名校验但bundle未签名时应当拒绝导入，但返回了nil")
+	}
+}
+
+func TestImportBundleConflictPolicies(t *testing.T) {
+	src := newTestConfigManager(t)
+	mustAddConfig(t, src, "prod")
+
+	var buf bytes.Buffer
+	if err := src.ExportBundle(nil, &buf, ExportOptions{}); err != nil {
+		t.Fatalf("ExportBundle失败: %v", err)
+	}
+	bundleBytes := buf.Bytes()
+
+	t.Run("skip", func(t *testing.T) {
+		dst := newTestConfigManager(t)
+		mustAddConfig(t, dst, "prod")
+		imported, err := dst.ImportBundle(bytes.NewReader(bundleBytes), ImportOptions{Conflict: ImportSkip})
+		if err != nil {
+			t.Fatalf("ImportBundle失败: %v", err)
+		}
+		if len(imported) != 0 {
+			t.Fatalf("ImportSkip遇到同名配置应当跳过，got %v", imported)
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		dst := newTestConfigManager(t)
+		mustAddConfig(t, dst, "prod")
+		imported, err := dst.ImportBundle(bytes.NewReader(bundleBytes), ImportOptions{Conflict: ImportRename})
+		if err != nil {
+			t.Fatalf("ImportBundle失败: %v", err)
+		}
+		if len(imported) != 1 || imported[0] == "prod" {
+			t.Fatalf("ImportRename遇到同名配置应当改名导入，got %v", imported)
+		}
+		if _, err := dst.GetConfig(imported[0]); err != nil {
+			t.Fatalf("改名后的配置应当可以读取: %v", err)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		dst := newTestConfigManager(t)
+		existing := mustAddConfig(t, dst, "prod")
+		existing.SSH.Host = "stale.example.com"
+		if err := dst.UpdateConfig("prod", existing); err != nil {
+			t.Fatalf("UpdateConfig失败: %v", err)
+		}
+
+		imported, err := dst.ImportBundle(bytes.NewReader(bundleBytes), ImportOptions{Conflict: ImportOverwrite})
+		if err != nil {
+			t.Fatalf("ImportBundle失败: %v", err)
+		}
+		if len(imported) != 1 || imported[0] != "prod" {
+			t.Fatalf("ImportOverwrite应当覆盖同名配置，got %v", imported)
+		}
+
+		got, err := dst.GetConfig("prod")
+		if err != nil {
+			t.Fatalf("GetConfig失败: %v", err)
+		}
+		if got.SSH.Host != "example.com" {
+			t.Fatalf("覆盖导入后配置应当变为bundle里的内容，got host=%q", got.SSH.Host)
+		}
+	})
+}
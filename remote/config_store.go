@@ -0,0 +1,199 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigStore 是远程同步配置持久化层的抽象：ConfigManager 的校验、默认值
+// 填充等业务逻辑都与具体存储格式无关，真正的读写交给 ConfigStore 的实现。
+// 内置实现包括 JSON 文件（jsonFileConfigStore，唯一支持 config_vault.go
+// 加密信封的一个）、INI、YAML、只读的环境变量后端（独立的一种后端，
+// 只读取环境变量，不叠加在其他后端之上），以及 etcd/Consul/Redis 远程 KV
+// （统一由 config_store_kv.go 承载）。
+type ConfigStore interface {
+	// Load 读取存储中的全部配置。
+	Load() (map[string]*RemoteConfig, error)
+	// Save 把全部配置整体写回存储。
+	Save(configs map[string]*RemoteConfig) error
+	// Get 读取单个配置，不存在时返回错误。
+	Get(name string) (*RemoteConfig, error)
+	// List 返回存储中的全部配置，语义等同 Load；部分后端（如远程 KV）能
+	// 用比整份反序列化更轻量的方式实现它，因此单独作为接口方法暴露。
+	List() (map[string]*RemoteConfig, error)
+	// Put 写入/覆盖单个配置。
+	Put(name string, config *RemoteConfig) error
+	// Delete 删除单个配置。
+	Delete(name string) error
+}
+
+// StoreType 标识 ConfigStore 的具体实现，也是 backend.json 里 "type" 字段
+// 的取值。
+type StoreType string
+
+const (
+	StoreTypeJSON   StoreType = "json"
+	StoreTypeINI    StoreType = "ini"
+	StoreTypeYAML   StoreType = "yaml"
+	StoreTypeEnv    StoreType = "env"
+	StoreTypeEtcd   StoreType = "etcd"
+	StoreTypeConsul StoreType = "consul"
+	StoreTypeRedis  StoreType = "redis"
+)
+
+// StoreOption 告诉 NewConfigManager 应该打开哪种 ConfigStore。Type 留空
+// 表示"沿用上次 `config backend set` 选定的后端，否则用 JSON 文件"，其余
+// 字段按 Type 解释：
+//   - json/ini/yaml：ConfigDir 为空时默认 ~/.synccli；
+//   - etcd/consul/redis：Addr 是连接地址，Prefix 是键前缀。
+type StoreOption struct {
+	Type      StoreType
+	ConfigDir string
+	Addr      string
+	Prefix    string
+}
+
+// storeSelector 是 ~/.synccli/backend.json 的内容：记录上一次通过
+// `synccli remote config backend set` 选定的后端，让后续不传 StoreOption
+// 的 NewConfigManager 调用也能打开同一个后端。
+type storeSelector struct {
+	Type   StoreType `json:"type"`
+	Addr   string    `json:"addr,omitempty"`
+	Prefix string    `json:"prefix,omitempty"`
+}
+
+func backendSelectorFile(configDir string) string {
+	return filepath.Join(configDir, "backend.json")
+}
+
+func loadStoreSelector(configDir string) (storeSelector, bool) {
+	data, err := os.ReadFile(backendSelectorFile(configDir))
+	if err != nil {
+		return storeSelector{}, false
+	}
+	var sel storeSelector
+	if err := json.Unmarshal(data, &sel); err != nil {
+		return storeSelector{}, false
+	}
+	return sel, sel.Type != ""
+}
+
+func saveStoreSelector(configDir string, sel storeSelector) error {
+	data, err := json.MarshalIndent(sel, "", " ")
+	if err != nil {
+		return fmt.Errorf("序列化后端选择失败: %v", err)
+	}
+	return os.WriteFile(backendSelectorFile(configDir), data, 0644)
+}
+
+// newConfigStore 根据 opt 构造具体的 ConfigStore 实现，opt.Type 为空时回退
+// 到 backend.json 记录的选择，再回退到 StoreTypeJSON。
+func newConfigStore(configDir string, opt StoreOption) (ConfigStore, error) {
+	storeType := opt.Type
+	addr := opt.Addr
+	prefix := opt.Prefix
+
+	if storeType == "" {
+		if sel, ok := loadStoreSelector(configDir); ok {
+			storeType = sel.Type
+			if addr == "" {
+				addr = sel.Addr
+			}
+			if prefix == "" {
+				prefix = sel.Prefix
+			}
+		} else {
+			storeType = StoreTypeJSON
+		}
+	}
+
+	switch storeType {
+	case StoreTypeJSON:
+		return newJSONFileConfigStore(filepath.Join(configDir, "remote_configs.json")), nil
+	case StoreTypeINI:
+		return newINIConfigStore(filepath.Join(configDir, "remote_configs.ini")), nil
+	case StoreTypeYAML:
+		return newYAMLConfigStore(filepath.Join(configDir, "remote_configs.yaml")), nil
+	case StoreTypeEnv:
+		return newEnvConfigStore(), nil
+	case StoreTypeEtcd:
+		return newEtcdConfigStore(addr, prefix)
+	case StoreTypeConsul:
+		return newConsulConfigStore(addr, prefix)
+	case StoreTypeRedis:
+		return newRedisConfigStore(addr, prefix)
+	default:
+		return nil, fmt.Errorf("不支持的配置后端类型: %s", storeType)
+	}
+}
+
+// loadSaver 是能整份读写配置表的最小接口。genericGet/genericList/
+// genericPut/genericDelete 基于它实现 ConfigStore 的单条目操作，供没有
+// 原生增量更新能力的文件类后端（JSON/INI/YAML）复用，避免三份重复代码。
+type loadSaver interface {
+	Load() (map[string]*RemoteConfig, error)
+	Save(configs map[string]*RemoteConfig) error
+}
+
+func genericGet(s loadSaver, name string) (*RemoteConfig, error) {
+	configs, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	config, ok := configs[name]
+	if !ok {
+		return nil, fmt.Errorf("this is null: %s", name)
+	}
+	return config, nil
+}
+
+func genericPut(s loadSaver, name string, config *RemoteConfig) error {
+	configs, err := s.Load()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		configs = make(map[string]*RemoteConfig)
+	}
+	configs[name] = config
+	return s.Save(configs)
+}
+
+func genericDelete(s loadSaver, name string) error {
+	configs, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := configs[name]; !ok {
+		return fmt.Errorf("this is null: %s", name)
+	}
+	delete(configs, name)
+	return s.Save(configs)
+}
+
+// MigrateStore 把当前全部配置整体搬到 opt 指定的新后端，并把这次选择记录
+// 进 backend.json，供之后不带 StoreOption 的 NewConfigManager 调用沿用。
+// 供 CLI 的 `synccli remote config backend set` 使用。
+func (cm *ConfigManager) MigrateStore(opt StoreOption) error {
+	configs, err := cm.store.Load()
+	if err != nil {
+		return fmt.Errorf("读取当前配置后端失败: %v", err)
+	}
+
+	newStore, err := newConfigStore(cm.configDir, opt)
+	if err != nil {
+		return err
+	}
+	if err := newStore.Save(configs); err != nil {
+		return fmt.Errorf("写入新配置后端失败: %v", err)
+	}
+
+	if err := saveStoreSelector(cm.configDir, storeSelector{Type: opt.Type, Addr: opt.Addr, Prefix: opt.Prefix}); err != nil {
+		return err
+	}
+
+	cm.store = newStore
+	return nil
+}
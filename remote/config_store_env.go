@@ -0,0 +1,89 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// envConfigStore 从形如 SYNCCLI_<NAME>_SSH_HOST 的环境变量里重建配置表，
+// 面向 CI 场景：流水线只需设置几个环境变量，不必在运行器上落地任何配置
+// 文件。它是只读的——没有文件或远程服务可写，Save/Put/Delete 直接报错。
+//
+// 支持的变量（<NAME> 是配置名，需要是合法的环境变量片段，例如用下划线
+// 代替横杠）：
+//
+//	SYNCCLI_<NAME>_SSH_HOST       （必需，缺失则该配置不会出现）
+//	SYNCCLI_<NAME>_SSH_PORT       （默认 22）
+//	SYNCCLI_<NAME>_SSH_USERNAME
+//	SYNCCLI_<NAME>_SSH_PASSWORD
+//	SYNCCLI_<NAME>_SSH_KEYFILE
+//	SYNCCLI_<NAME>_REMOTE_BASE
+type envConfigStore struct{}
+
+func newEnvConfigStore() *envConfigStore {
+	return &envConfigStore{}
+}
+
+var envConfigNamePattern = regexp.MustCompile(`^SYNCCLI_(.+)_SSH_HOST$`)
+
+func (s *envConfigStore) Load() (map[string]*RemoteConfig, error) {
+	configs := make(map[string]*RemoteConfig)
+
+	for _, entry := range os.Environ() {
+		key := strings.SplitN(entry, "=", 2)[0]
+		m := envConfigNamePattern.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+
+		port := 22
+		if v := os.Getenv(fmt.Sprintf("SYNCCLI_%s_SSH_PORT", name)); v != "" {
+			if p, err := strconv.Atoi(v); err == nil {
+				port = p
+			}
+		}
+
+		configs[name] = &RemoteConfig{
+			Name:     name,
+			Protocol: ProtocolSSH,
+			SSH: &SSHConfig{
+				Host:     os.Getenv(fmt.Sprintf("SYNCCLI_%s_SSH_HOST", name)),
+				Port:     port,
+				Username: os.Getenv(fmt.Sprintf("SYNCCLI_%s_SSH_USERNAME", name)),
+				Password: os.Getenv(fmt.Sprintf("SYNCCLI_%s_SSH_PASSWORD", name)),
+				KeyFile:  os.Getenv(fmt.Sprintf("SYNCCLI_%s_SSH_KEYFILE", name)),
+				Timeout:  30,
+			},
+			RemoteBase: os.Getenv(fmt.Sprintf("SYNCCLI_%s_REMOTE_BASE", name)),
+		}
+	}
+
+	return configs, nil
+}
+
+var errEnvStoreReadOnly = errors.New("环境变量配置后端是只读的，不支持写入")
+
+func (s *envConfigStore) Save(configs map[string]*RemoteConfig) error {
+	return errEnvStoreReadOnly
+}
+
+func (s *envConfigStore) Get(name string) (*RemoteConfig, error) {
+	return genericGet(s, name)
+}
+
+func (s *envConfigStore) List() (map[string]*RemoteConfig, error) {
+	return s.Load()
+}
+
+func (s *envConfigStore) Put(name string, config *RemoteConfig) error {
+	return errEnvStoreReadOnly
+}
+
+func (s *envConfigStore) Delete(name string) error {
+	return errEnvStoreReadOnly
+}
@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// iniConfigStore 把配置表保存成一个 INI 文件：每个配置一个 section，
+// section 名就是配置名，方便用户直接用编辑器手改 ~/.synccli/remote_configs.ini。
+type iniConfigStore struct {
+	configFile string
+}
+
+func newINIConfigStore(configFile string) *iniConfigStore {
+	return &iniConfigStore{configFile: configFile}
+}
+
+func (s *iniConfigStore) Load() (map[string]*RemoteConfig, error) {
+	file, err := ini.Load(s.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]*RemoteConfig)
+	for _, section := range file.Sections() {
+		if section.Name() == ini.DefaultSection {
+			continue
+		}
+
+		configs[section.Name()] = &RemoteConfig{
+			Name:     section.Name(),
+			Protocol: section.Key("protocol").MustString(ProtocolSSH),
+			SSH: &SSHConfig{
+				Host:            section.Key("ssh_host").String(),
+				Port:            section.Key("ssh_port").MustInt(22),
+				Username:        section.Key("ssh_username").String(),
+				Password:        section.Key("ssh_password").String(),
+				KeyFile:         section.Key("ssh_keyfile").String(),
+				Timeout:         section.Key("ssh_timeout").MustInt(30),
+				KnownHostsFile:  section.Key("known_hosts_file").String(),
+				StrictHostCheck: section.Key("strict_host_check").MustBool(true),
+			},
+			RemoteBase:  section.Key("remote_base").String(),
+			Compression: section.Key("compression").MustBool(true),
+			Encryption:  section.Key("encryption").MustBool(true),
+			Incremental: section.Key("incremental").MustBool(true),
+			ExcludeList: splitINIList(section.Key("exclude_list").String()),
+		}
+	}
+	return configs, nil
+}
+
+func (s *iniConfigStore) Save(configs map[string]*RemoteConfig) error {
+	file := ini.Empty()
+	for name, config := range configs {
+		section, err := file.NewSection(name)
+		if err != nil {
+			return fmt.Errorf("创建INI分区失败 %s: %v", name, err)
+		}
+
+		section.Key("protocol").SetValue(config.Protocol)
+		if config.SSH != nil {
+			section.Key("ssh_host").SetValue(config.SSH.Host)
+			section.Key("ssh_port").SetValue(fmt.Sprintf("%d", config.SSH.Port))
+			section.Key("ssh_username").SetValue(config.SSH.Username)
+			section.Key("ssh_password").SetValue(config.SSH.Password)
+			section.Key("ssh_keyfile").SetValue(config.SSH.KeyFile)
+			section.Key("ssh_timeout").SetValue(fmt.Sprintf("%d", config.SSH.Timeout))
+			section.Key("known_hosts_file").SetValue(config.SSH.KnownHostsFile)
+			section.Key("strict_host_check").SetValue(fmt.Sprintf("%v", config.SSH.StrictHostCheck))
+		}
+		section.Key("remote_base").SetValue(config.RemoteBase)
+		section.Key("compression").SetValue(fmt.Sprintf("%v", config.Compression))
+		section.Key("encryption").SetValue(fmt.Sprintf("%v", config.Encryption))
+		section.Key("incremental").SetValue(fmt.Sprintf("%v", config.Incremental))
+		section.Key("exclude_list").SetValue(strings.Join(config.ExcludeList, ","))
+	}
+
+	return file.SaveTo(s.configFile)
+}
+
+func (s *iniConfigStore) Get(name string) (*RemoteConfig, error) {
+	return genericGet(s, name)
+}
+
+func (s *iniConfigStore) List() (map[string]*RemoteConfig, error) {
+	return s.Load()
+}
+
+func (s *iniConfigStore) Put(name string, config *RemoteConfig) error {
+	return genericPut(s, name, config)
+}
+
+func (s *iniConfigStore) Delete(name string) error {
+	return genericDelete(s, name)
+}
+
+// ConfigFilePath 供 config_watch.go 的 fsnotify 热加载使用。
+func (s *iniConfigStore) ConfigFilePath() string {
+	return s.configFile
+}
+
+func splitINIList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,214 @@
+package remote
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// jsonFileConfigStore 是 ConfigStore 的默认实现：把配置表整体序列化成一个
+// JSON 文件。它也是唯一支持 config_vault.go 加密信封的后端——Unlock 之前
+// 派生的密钥、盐值与锁定状态都保存在这里，而不是 ConfigManager 上，因为
+// 这些概念只对"整份文件落盘"这种存储形态有意义。
+type jsonFileConfigStore struct {
+	configFile string
+
+	vaultSalt       []byte
+	vaultKey        []byte
+	locked          bool
+	pendingEnvelope []byte
+}
+
+func newJSONFileConfigStore(configFile string) *jsonFileConfigStore {
+	return &jsonFileConfigStore{configFile: configFile}
+}
+
+// Load 读取配置文件。对旧版明文文件直接反序列化；若文件是加密信封，已经
+// 解锁过（vaultKey 非空）就直接解密，否则尝试用系统密钥串中缓存的口令
+// 自动解锁，都不行就返回 ErrVaultLocked，调用方需要显式调用 Unlock。
+func (s *jsonFileConfigStore) Load() (map[string]*RemoteConfig, error) {
+	data, err := os.ReadFile(s.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isVaultEnvelope(data) {
+		configs := make(map[string]*RemoteConfig)
+		if err := json.Unmarshal(data, &configs); err != nil {
+			return nil, err
+		}
+		return configs, nil
+	}
+
+	var envelope configVaultEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("解析配置信封失败: %v", err)
+	}
+	s.vaultSalt = envelope.Salt
+
+	if s.vaultKey != nil {
+		if configs, err := openConfigs(data, s.vaultKey); err == nil {
+			s.locked = false
+			return configs, nil
+		}
+	}
+
+	if passphrase, ok := lookupCachedVaultPassphrase(); ok {
+		if key, err := deriveVaultKey([]byte(passphrase), s.vaultSalt); err == nil {
+			if configs, err := openConfigs(data, key); err == nil {
+				s.vaultKey = key
+				s.locked = false
+				return configs, nil
+			}
+		}
+	}
+
+	s.locked = true
+	s.pendingEnvelope = data
+	return nil, ErrVaultLocked
+}
+
+// Save 把 configs 整体写回文件。仓库从未启用加密时照旧写明文；一旦调用过
+// Unlock，之后的每次保存都会迁移/保持为加密信封格式。
+func (s *jsonFileConfigStore) Save(configs map[string]*RemoteConfig) error {
+	if s.locked {
+		return ErrVaultLocked
+	}
+	if s.vaultKey == nil {
+		data, err := json.MarshalIndent(configs, "", " ")
+		if err != nil {
+			return fmt.Errorf("序列化配置失败: %v", err)
+		}
+		return os.WriteFile(s.configFile, data, 0600)
+	}
+
+	data, err := sealConfigs(configs, s.vaultSalt, s.vaultKey)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.configFile, data, 0600)
+}
+
+func (s *jsonFileConfigStore) Get(name string) (*RemoteConfig, error) {
+	return genericGet(s, name)
+}
+
+func (s *jsonFileConfigStore) List() (map[string]*RemoteConfig, error) {
+	return s.Load()
+}
+
+func (s *jsonFileConfigStore) Put(name string, config *RemoteConfig) error {
+	return genericPut(s, name, config)
+}
+
+func (s *jsonFileConfigStore) Delete(name string) error {
+	return genericDelete(s, name)
+}
+
+// ConfigFilePath 供 config_watch.go 的 fsnotify 热加载使用。
+func (s *jsonFileConfigStore) ConfigFilePath() string {
+	return s.configFile
+}
+
+// IsLocked 报告这个 JSON 文件仓库是否已加密且当前处于锁定状态。
+func (s *jsonFileConfigStore) IsLocked() bool {
+	return s.locked
+}
+
+// Unlock 用 passphrase 解锁仓库：
+//   - 若仓库此前从未加密（vaultSalt 为空，通常是刚从旧版明文文件加载而
+//     来），会为其生成新的盐值，之后的 Save 将自动迁移为加密信封；
+//   - 若仓库已是加密信封且处于锁定状态（Load 返回了 ErrVaultLocked），
+//     会先用 passphrase 试解密 pendingEnvelope 校验口令是否正确。
+//
+// 解锁成功后会尝试把口令缓存进系统密钥串，后续启动无需再次输入；缓存失败
+// 不影响本次解锁，只是下次仍需手动输入。
+func (s *jsonFileConfigStore) Unlock(passphrase string) error {
+	if s.vaultSalt == nil {
+		salt := make([]byte, configVaultSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("生成配置仓库盐值失败: %v", err)
+		}
+		s.vaultSalt = salt
+	}
+
+	key, err := deriveVaultKey([]byte(passphrase), s.vaultSalt)
+	if err != nil {
+		return err
+	}
+
+	if s.locked {
+		if _, err := openConfigs(s.pendingEnvelope, key); err != nil {
+			return err
+		}
+		s.pendingEnvelope = nil
+	}
+
+	s.vaultKey = key
+	s.locked = false
+
+	if err := cacheVaultPassphrase(passphrase); err != nil {
+		fmt.Printf("警告: 缓存口令到系统密钥串失败，下次启动仍需手动解锁: %v\n", err)
+	}
+	return nil
+}
+
+// Lock 清除内存中的派生密钥与系统密钥串缓存，此后读写都需要重新调用
+// Unlock。对从未启用过加密的仓库调用是无操作。
+func (s *jsonFileConfigStore) Lock() error {
+	if s.vaultSalt == nil {
+		return nil
+	}
+
+	s.vaultKey = nil
+	s.locked = true
+
+	return forgetCachedVaultPassphrase()
+}
+
+// ChangePassphrase 校验 oldPassphrase 无误后，改用 newPassphrase 派生新的
+// 盐值和密钥并立即重新加密落盘；旧密文不会被新密钥复用。对尚未加密过的
+// 仓库调用等价于首次启用加密，oldPassphrase 会被忽略。
+func (s *jsonFileConfigStore) ChangePassphrase(oldPassphrase, newPassphrase string) error {
+	if s.locked {
+		return ErrVaultLocked
+	}
+
+	if s.vaultSalt != nil {
+		oldKey, err := deriveVaultKey([]byte(oldPassphrase), s.vaultSalt)
+		if err != nil {
+			return err
+		}
+		if s.vaultKey == nil || subtle.ConstantTimeCompare(oldKey, s.vaultKey) != 1 {
+			return fmt.Errorf("旧口令不正确")
+		}
+	}
+
+	newSalt := make([]byte, configVaultSaltSize)
+	if _, err := rand.Read(newSalt); err != nil {
+		return fmt.Errorf("生成配置仓库盐值失败: %v", err)
+	}
+	newKey, err := deriveVaultKey([]byte(newPassphrase), newSalt)
+	if err != nil {
+		return err
+	}
+
+	configs, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	s.vaultSalt = newSalt
+	s.vaultKey = newKey
+
+	if err := s.Save(configs); err != nil {
+		return err
+	}
+
+	if err := cacheVaultPassphrase(newPassphrase); err != nil {
+		fmt.Printf("警告: 缓存口令到系统密钥串失败，下次启动仍需手动解锁: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// kvClient 是远程 KV 存储的最小抽象，让 etcd/Consul/Redis 三种客户端库
+// 之间共享同一套 kvConfigStore 逻辑——与 Backend 接口统一 SFTP/本地/
+// WebDAV/S3 背后的最小操作集是同样的思路（参见 backend.go）。
+type kvClient interface {
+	Get(key string) ([]byte, bool, error)
+	Put(key string, value []byte) error
+	Delete(key string) error
+	List(prefix string) (map[string][]byte, error)
+}
+
+// kvConfigStore 把每个配置序列化成一条独立的 KV 记录（key = prefix+name），
+// 不像 JSON/INI/YAML 那样整份读写，增删单个配置不需要搬动其它配置，更贴近
+// etcd/Consul/Redis 的典型用法。
+type kvConfigStore struct {
+	client kvClient
+	prefix string
+}
+
+func newKVConfigStore(client kvClient, prefix string) *kvConfigStore {
+	if prefix == "" {
+		prefix = "synccli/remote-configs/"
+	} else if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &kvConfigStore{client: client, prefix: prefix}
+}
+
+func (s *kvConfigStore) key(name string) string {
+	return s.prefix + name
+}
+
+func (s *kvConfigStore) Load() (map[string]*RemoteConfig, error) {
+	return s.List()
+}
+
+// Save 把 configs 同步成 KV 里的唯一真相：先删掉不在 configs 里的旧键，
+// 再逐条 Put 其余配置。
+func (s *kvConfigStore) Save(configs map[string]*RemoteConfig) error {
+	existing, err := s.List()
+	if err != nil {
+		return err
+	}
+	for name := range existing {
+		if _, keep := configs[name]; !keep {
+			if err := s.Delete(name); err != nil {
+				return err
+			}
+		}
+	}
+	for name, config := range configs {
+		if err := s.Put(name, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *kvConfigStore) Get(name string) (*RemoteConfig, error) {
+	data, ok, err := s.client.Get(s.key(name))
+	if err != nil {
+		return nil, fmt.Errorf("读取远程KV配置失败 %s: %v", name, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("this is null: %s", name)
+	}
+
+	var config RemoteConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("解析远程KV配置失败 %s: %v", name, err)
+	}
+	return &config, nil
+}
+
+func (s *kvConfigStore) List() (map[string]*RemoteConfig, error) {
+	raw, err := s.client.List(s.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程KV配置失败: %v", err)
+	}
+
+	configs := make(map[string]*RemoteConfig, len(raw))
+	for key, data := range raw {
+		name := strings.TrimPrefix(key, s.prefix)
+		var config RemoteConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("解析远程KV配置失败 %s: %v", name, err)
+		}
+		configs[name] = &config
+	}
+	return configs, nil
+}
+
+func (s *kvConfigStore) Put(name string, config *RemoteConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+	if err := s.client.Put(s.key(name), data); err != nil {
+		return fmt.Errorf("写入远程KV配置失败 %s: %v", name, err)
+	}
+	return nil
+}
+
+func (s *kvConfigStore) Delete(name string) error {
+	if err := s.client.Delete(s.key(name)); err != nil {
+		return fmt.Errorf("删除远程KV配置失败 %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// consulKVClient 是 kvClient 在 Consul 的 KV 存储上的实现。
+type consulKVClient struct {
+	client *api.Client
+}
+
+// newConsulConfigStore 创建一个以 Consul KV 为后端的 ConfigStore，addr 为
+// 空时使用 Consul 客户端库的默认地址（通常是 127.0.0.1:8500）。
+func newConsulConfigStore(addr, prefix string) (ConfigStore, error) {
+	cfg := api.DefaultConfig()
+	if addr != "" {
+		cfg.Address = addr
+	}
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("连接Consul失败 %s: %v", addr, err)
+	}
+
+	return newKVConfigStore(&consulKVClient{client: client}, prefix), nil
+}
+
+func (c *consulKVClient) Get(key string) ([]byte, bool, error) {
+	pair, _, err := c.client.KV().Get(key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if pair == nil {
+		return nil, false, nil
+	}
+	return pair.Value, true, nil
+}
+
+func (c *consulKVClient) Put(key string, value []byte) error {
+	_, err := c.client.KV().Put(&api.KVPair{Key: key, Value: value}, nil)
+	return err
+}
+
+func (c *consulKVClient) Delete(key string) error {
+	_, err := c.client.KV().Delete(key, nil)
+	return err
+}
+
+func (c *consulKVClient) List(prefix string) (map[string][]byte, error) {
+	pairs, _, err := c.client.KV().List(prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(pairs))
+	for _, pair := range pairs {
+		result[pair.Key] = pair.Value
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdKVClient 是 kvClient 在 etcd 上的实现。
+type etcdKVClient struct {
+	client *clientv3.Client
+}
+
+// newEtcdConfigStore 创建一个以 etcd 为后端的 ConfigStore，addr 为空时
+// 连接本机默认端口。
+func newEtcdConfigStore(addr, prefix string) (ConfigStore, error) {
+	if addr == "" {
+		addr = "127.0.0.1:2379"
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{addr},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接etcd失败 %s: %v", addr, err)
+	}
+
+	return newKVConfigStore(&etcdKVClient{client: client}, prefix), nil
+}
+
+func (c *etcdKVClient) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.Get(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, false, nil
+	}
+	return resp.Kvs[0].Value, true, nil
+}
+
+func (c *etcdKVClient) Put(key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.Put(ctx, key, string(value))
+	return err
+}
+
+func (c *etcdKVClient) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.Delete(ctx, key)
+	return err
+}
+
+func (c *etcdKVClient) List(prefix string) (map[string][]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		result[string(kv.Key)] = kv.Value
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKVClient 是 kvClient 在 Redis 上的实现，每个配置一个字符串键。
+type redisKVClient struct {
+	client *redis.Client
+}
+
+// newRedisConfigStore 创建一个以 Redis 为后端的 ConfigStore，addr 为空时
+// 连接本机默认端口。
+func newRedisConfigStore(addr, prefix string) (ConfigStore, error) {
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	return newKVConfigStore(&redisKVClient{client: client}, prefix), nil
+}
+
+func (c *redisKVClient) Get(key string) ([]byte, bool, error) {
+	data, err := c.client.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (c *redisKVClient) Put(key string, value []byte) error {
+	return c.client.Set(context.Background(), key, value, 0).Err()
+}
+
+func (c *redisKVClient) Delete(key string) error {
+	return c.client.Del(context.Background(), key).Err()
+}
+
+func (c *redisKVClient) List(prefix string) (map[string][]byte, error) {
+	ctx := context.Background()
+	result := make(map[string][]byte)
+
+	iter := c.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		data, err := c.client.Get(ctx, key).Bytes()
+		if err != nil {
+			return nil, err
+		}
+		result[key] = data
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("扫描Redis键失败: %v", err)
+	}
+	return result, nil
+}
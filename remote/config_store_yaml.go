@@ -0,0 +1,60 @@
+package remote
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlConfigStore 把配置表整体序列化成一个 YAML 文件，结构与 JSON 文件
+// 后端一致（同一组 yaml 结构体标签），只是换了种人类更容易手改的格式。
+type yamlConfigStore struct {
+	configFile string
+}
+
+func newYAMLConfigStore(configFile string) *yamlConfigStore {
+	return &yamlConfigStore{configFile: configFile}
+}
+
+func (s *yamlConfigStore) Load() (map[string]*RemoteConfig, error) {
+	data, err := os.ReadFile(s.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]*RemoteConfig)
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("解析YAML配置失败: %v", err)
+	}
+	return configs, nil
+}
+
+func (s *yamlConfigStore) Save(configs map[string]*RemoteConfig) error {
+	data, err := yaml.Marshal(configs)
+	if err != nil {
+		return fmt.Errorf("序列化YAML配置失败: %v", err)
+	}
+	return os.WriteFile(s.configFile, data, 0600)
+}
+
+func (s *yamlConfigStore) Get(name string) (*RemoteConfig, error) {
+	return genericGet(s, name)
+}
+
+func (s *yamlConfigStore) List() (map[string]*RemoteConfig, error) {
+	return s.Load()
+}
+
+func (s *yamlConfigStore) Put(name string, config *RemoteConfig) error {
+	return genericPut(s, name, config)
+}
+
+func (s *yamlConfigStore) Delete(name string) error {
+	return genericDelete(s, name)
+}
+
+// ConfigFilePath 供 config_watch.go 的 fsnotify 热加载使用。
+func (s *yamlConfigStore) ConfigFilePath() string {
+	return s.configFile
+}
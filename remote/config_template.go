@@ -0,0 +1,189 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Templates 与具体配置（config.go 里的 RemoteConfig 表）分开持久化，放在
+// ~/.synccli/templates.json，不受 config backend set（参见 config_store.go）
+// 选择的存储后端影响——模板是轻量、很少写入的基线定义，没必要跟着配置
+// 表一起搬迁。
+
+func templatesFile(configDir string) string {
+	return filepath.Join(configDir, "templates.json")
+}
+
+func loadTemplates(configDir string) (map[string]*RemoteConfig, error) {
+	data, err := os.ReadFile(templatesFile(configDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]*RemoteConfig), nil
+		}
+		return nil, err
+	}
+
+	templates := make(map[string]*RemoteConfig)
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("解析模板文件失败: %v", err)
+	}
+	return templates, nil
+}
+
+func saveTemplates(configDir string, templates map[string]*RemoteConfig) error {
+	data, err := json.MarshalIndent(templates, "", " ")
+	if err != nil {
+		return fmt.Errorf("序列化模板文件失败: %v", err)
+	}
+	return os.WriteFile(templatesFile(configDir), data, 0600)
+}
+
+// AddTemplate 新增或覆盖一个可供其它配置通过 Extends 引用的模板。
+func (cm *ConfigManager) AddTemplate(name string, template *RemoteConfig) error {
+	if name == "" {
+		return fmt.Errorf("the name is null")
+	}
+
+	templates, err := loadTemplates(cm.configDir)
+	if err != nil {
+		return err
+	}
+
+	template.Name = name
+	templates[name] = template
+	return saveTemplates(cm.configDir, templates)
+}
+
+// ResolveConfig 读取指定配置，如果它声明了 Extends 就与同名模板做深度合
+// 并（子配置的非零字段覆盖模板，ExcludeList 是父子拼接后去重），最后对
+// Host/Username/Password/KeyFile/KnownHostsFile/RemoteBase 这些字符串字
+// 段做形如 "${SSH_KEY_HOME}/id_ed25519" 的环境变量插值。没有 Extends 时
+// 只做插值，不做合并。
+func (cm *ConfigManager) ResolveConfig(name string) (*RemoteConfig, error) {
+	config, err := cm.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Extends != "" {
+		templates, err := loadTemplates(cm.configDir)
+		if err != nil {
+			return nil, err
+		}
+		parent, ok := templates[config.Extends]
+		if !ok {
+			return nil, fmt.Errorf("找不到模板: %s", config.Extends)
+		}
+		config = mergeConfig(parent, config)
+	}
+
+	return interpolateConfig(config), nil
+}
+
+// mergeConfig 以 parent 为基底、child 为覆盖层做深度合并：child 里的零值
+// 字段（""、0、false）视为"未显式设置"，沿用 parent 的值；ExcludeList
+// 是父子拼接后去重，而不是整体替换。
+func mergeConfig(parent, child *RemoteConfig) *RemoteConfig {
+	merged := *parent
+	merged.Name = child.Name
+	merged.Extends = child.Extends
+
+	if child.Protocol != "" {
+		merged.Protocol = child.Protocol
+	}
+	if child.RemoteBase != "" {
+		merged.RemoteBase = child.RemoteBase
+	}
+	if child.Target != "" {
+		merged.Target = child.Target
+	}
+	if child.KnownHostsFile != "" {
+		merged.KnownHostsFile = child.KnownHostsFile
+	}
+	if child.Compression {
+		merged.Compression = true
+	}
+	if child.Encryption {
+		merged.Encryption = true
+	}
+	if child.Incremental {
+		merged.Incremental = true
+	}
+	if child.StrictHostCheck {
+		merged.StrictHostCheck = true
+	}
+	merged.ExcludeList = dedupeStrings(append(append([]string{}, parent.ExcludeList...), child.ExcludeList...))
+
+	switch {
+	case child.SSH != nil:
+		sshMerged := *child.SSH
+		if parent.SSH != nil {
+			if child.SSH.Host == "" {
+				sshMerged.Host = parent.SSH.Host
+			}
+			if child.SSH.Port == 0 {
+				sshMerged.Port = parent.SSH.Port
+			}
+			if child.SSH.Username == "" {
+				sshMerged.Username = parent.SSH.Username
+			}
+			if child.SSH.Password == "" {
+				sshMerged.Password = parent.SSH.Password
+			}
+			if child.SSH.KeyFile == "" {
+				sshMerged.KeyFile = parent.SSH.KeyFile
+			}
+			if child.SSH.Timeout == 0 {
+				sshMerged.Timeout = parent.SSH.Timeout
+			}
+			if child.SSH.KnownHostsFile == "" {
+				sshMerged.KnownHostsFile = parent.SSH.KnownHostsFile
+			}
+			if !child.SSH.StrictHostCheck {
+				sshMerged.StrictHostCheck = parent.SSH.StrictHostCheck
+			}
+		}
+		merged.SSH = &sshMerged
+	case parent.SSH != nil:
+		sshCopy := *parent.SSH
+		merged.SSH = &sshCopy
+	}
+
+	return &merged
+}
+
+// dedupeStrings 保留首次出现的顺序，去掉重复项。
+func dedupeStrings(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// interpolateConfig 返回 config 的一份副本，对其中的路径/凭据类字符串字
+// 段做 os.ExpandEnv 风格的环境变量插值，不修改原始值（不然会把插值结果
+// 意外落盘）。
+func interpolateConfig(config *RemoteConfig) *RemoteConfig {
+	out := *config
+	out.RemoteBase = os.ExpandEnv(config.RemoteBase)
+	out.KnownHostsFile = os.ExpandEnv(config.KnownHostsFile)
+
+	if config.SSH != nil {
+		ssh := *config.SSH
+		ssh.Host = os.ExpandEnv(config.SSH.Host)
+		ssh.Username = os.ExpandEnv(config.SSH.Username)
+		ssh.Password = os.ExpandEnv(config.SSH.Password)
+		ssh.KeyFile = os.ExpandEnv(config.SSH.KeyFile)
+		ssh.KnownHostsFile = os.ExpandEnv(config.SSH.KnownHostsFile)
+		out.SSH = &ssh
+	}
+
+	return &out
+}
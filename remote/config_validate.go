@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// remoteConfigSchema 是 RemoteConfig 的 JSON Schema（draft-07），负责结构
+// 性约束（必填字段、端口范围、超时下限……）。glob 语法、密钥文件是否存
+// 在这类 schema 表达不了的语义检查，在 ValidateConfig 里单独补充。
+const remoteConfigSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["name", "ssh"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "ssh": {
+      "type": "object",
+      "required": ["host", "username"],
+      "properties": {
+        "host": {"type": "string", "minLength": 1},
+        "port": {"type": "integer", "minimum": 1, "maximum": 65535},
+        "username": {"type": "string", "minLength": 1},
+        "timeout": {"type": "integer", "minimum": 1}
+      }
+    }
+  }
+}`
+
+var remoteConfigSchemaLoader = gojsonschema.NewStringLoader(remoteConfigSchema)
+
+// ConfigFieldError 是一次校验中单个字段的失败，Path 是指向该字段的 JSON
+// 指针（如 "/ssh/port"），方便调用方逐项渲染诊断信息。
+type ConfigFieldError struct {
+	Path    string
+	Message string
+}
+
+func (e ConfigFieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ConfigValidationError 聚合一次 ValidateConfig 调用中的全部字段错误，
+// 取代旧版本"第一个错误就返回"的 fmt.Errorf，让调用方能一次性看到所有
+// 需要修正的地方。
+type ConfigValidationError struct {
+	Errors []ConfigFieldError
+}
+
+func (e *ConfigValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidateConfig 先用 remoteConfigSchema 做结构性校验，再补上 schema 表
+// 达不了的语义检查：ExcludeList 里每一项是否是合法的 glob、KeyFile（如
+// 果填了）是否真实存在。所有失败聚合进一个 *ConfigValidationError 返回；
+// 全部通过时返回 nil。
+func (cm *ConfigManager) ValidateConfig(config *RemoteConfig) error {
+	var fieldErrors []ConfigFieldError
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+
+	result, err := gojsonschema.Validate(remoteConfigSchemaLoader, gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("校验配置失败: %v", err)
+	}
+	for _, re := range result.Errors() {
+		fieldErrors = append(fieldErrors, ConfigFieldError{
+			Path:    schemaFieldToPointer(re.Field()),
+			Message: re.Description(),
+		})
+	}
+
+	for i, pattern := range config.ExcludeList {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			fieldErrors = append(fieldErrors, ConfigFieldError{
+				Path:    fmt.Sprintf("/excludeList/%d", i),
+				Message: fmt.Sprintf("无效的glob语法: %v", err),
+			})
+		}
+	}
+
+	if config.SSH != nil {
+		if config.SSH.Password == "" && config.SSH.KeyFile == "" {
+			fieldErrors = append(fieldErrors, ConfigFieldError{
+				Path:    "/ssh/password",
+				Message: "password 和 keyFile 必须至少填写一个",
+			})
+		}
+		if config.SSH.KeyFile != "" {
+			if _, err := os.Stat(config.SSH.KeyFile); os.IsNotExist(err) {
+				fieldErrors = append(fieldErrors, ConfigFieldError{
+					Path:    "/ssh/keyFile",
+					Message: fmt.Sprintf("密钥文件不存在: %s", config.SSH.KeyFile),
+				})
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return &ConfigValidationError{Errors: fieldErrors}
+	}
+	return nil
+}
+
+// schemaFieldToPointer 把 gojsonschema 的点号路径（如 "ssh.port"，根级别
+// 失败时是 "(root)"）转换成 JSON 指针风格（"/ssh/port" 或 "/"）。
+func schemaFieldToPointer(field string) string {
+	if field == "(root)" {
+		return "/"
+	}
+	return "/" + strings.ReplaceAll(field, ".", "/")
+}
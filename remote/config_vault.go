@@ -0,0 +1,186 @@
+package remote
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// remote_configs.json 既可能是旧版明文的 map[string]*RemoteConfig，也可能是
+// 本文件引入的加密信封：
+//
+//	{"synccliVault":1,"kdf":"scrypt","salt":"...","nonce":"...","ciphertext":"..."}
+//
+// ciphertext 是对明文配置表 JSON 整体做 XChaCha20-Poly1305 加密的结果，密钥
+// 由主口令通过 scrypt 派生。这套加密信封只有 jsonFileConfigStore
+// （config_store_json.go）支持——其它 ConfigStore 实现（INI/YAML/环境变量/
+// 远程 KV）各有自己的访问控制方式，不在这里重复。
+const (
+	configVaultVersion   = 1
+	configVaultKDFScrypt = "scrypt"
+	configVaultSaltSize  = 16
+
+	// scrypt 参数，在安全性与交互式解锁的响应速度之间取了个折中。
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrVaultLocked 表示配置仓库已启用加密但尚未解锁：Load 只读到了密文，
+// 调用方必须先调用 ConfigManager.Unlock 才能访问配置。
+var ErrVaultLocked = errors.New("远程配置仓库已加密锁定，请先调用 Unlock 解锁")
+
+// 用于在系统密钥串中缓存主口令，避免每次启动都重新输入主口令。
+const (
+	vaultKeyringService = "synccli"
+	vaultKeyringUser    = "remote-config-vault"
+)
+
+// configVaultEnvelope 是加密后落盘的信封结构。
+type configVaultEnvelope struct {
+	SyncCLIVault int    `json:"synccliVault"`
+	KDF          string `json:"kdf"`
+	Salt         []byte `json:"salt"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// isVaultEnvelope 通过嗅探 synccliVault 字段，区分磁盘上的 JSON 是旧版明文
+// 配置表还是加密信封。
+func isVaultEnvelope(data []byte) bool {
+	var probe struct {
+		SyncCLIVault int `json:"synccliVault"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.SyncCLIVault > 0
+}
+
+// deriveVaultKey 用 scrypt 从主口令和盐值派生出 XChaCha20-Poly1305 密钥。
+func deriveVaultKey(passphrase, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("派生配置仓库密钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// sealConfigs 把 configs 序列化后整体加密成信封 JSON。
+func sealConfigs(configs map[string]*RemoteConfig, salt, vaultKey []byte) ([]byte, error) {
+	plaintext, err := json.MarshalIndent(configs, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建XChaCha20-Poly1305失败: %v", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机nonce失败: %v", err)
+	}
+
+	envelope := configVaultEnvelope{
+		SyncCLIVault: configVaultVersion,
+		KDF:          configVaultKDFScrypt,
+		Salt:         salt,
+		Nonce:        nonce,
+		Ciphertext:   aead.Seal(nil, nonce, plaintext, nil),
+	}
+	return json.MarshalIndent(envelope, "", " ")
+}
+
+// openConfigs 是 sealConfigs 的逆过程，口令错误或密文被篡改都会在此报错。
+func openConfigs(data []byte, vaultKey []byte) (map[string]*RemoteConfig, error) {
+	var envelope configVaultEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("解析配置信封失败: %v", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建XChaCha20-Poly1305失败: %v", err)
+	}
+
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("解密配置失败（口令错误或文件已被篡改）: %v", err)
+	}
+
+	configs := make(map[string]*RemoteConfig)
+	if err := json.Unmarshal(plaintext, &configs); err != nil {
+		return nil, fmt.Errorf("解析解密后的配置失败: %v", err)
+	}
+	return configs, nil
+}
+
+func lookupCachedVaultPassphrase() (string, bool) {
+	passphrase, err := keyring.Get(vaultKeyringService, vaultKeyringUser)
+	if err != nil {
+		return "", false
+	}
+	return passphrase, true
+}
+
+func cacheVaultPassphrase(passphrase string) error {
+	return keyring.Set(vaultKeyringService, vaultKeyringUser, passphrase)
+}
+
+func forgetCachedVaultPassphrase() error {
+	if err := keyring.Delete(vaultKeyringService, vaultKeyringUser); err != nil && err != keyring.ErrNotFound {
+		return fmt.Errorf("清除系统密钥串缓存失败: %v", err)
+	}
+	return nil
+}
+
+// vaultStore 是支持加密信封的 ConfigStore 必须额外实现的接口，目前只有
+// jsonFileConfigStore 满足。ConfigManager 的 Unlock/Lock/ChangePassphrase/
+// IsLocked 通过把当前 store 断言成这个接口来转发调用。
+type vaultStore interface {
+	IsLocked() bool
+	Unlock(passphrase string) error
+	Lock() error
+	ChangePassphrase(oldPassphrase, newPassphrase string) error
+}
+
+// IsLocked 报告当前存储后端是否已加密且处于锁定状态。不支持加密的后端
+// （INI/YAML/环境变量/远程 KV）一律视为未锁定。
+func (cm *ConfigManager) IsLocked() bool {
+	vs, ok := cm.store.(vaultStore)
+	return ok && vs.IsLocked()
+}
+
+// Unlock 解锁当前存储后端的加密信封（仅 JSON 文件后端支持）。
+func (cm *ConfigManager) Unlock(passphrase string) error {
+	vs, ok := cm.store.(vaultStore)
+	if !ok {
+		return errors.New("当前配置后端不支持加密")
+	}
+	return vs.Unlock(passphrase)
+}
+
+// Lock 锁定当前存储后端（仅 JSON 文件后端支持）。
+func (cm *ConfigManager) Lock() error {
+	vs, ok := cm.store.(vaultStore)
+	if !ok {
+		return errors.New("当前配置后端不支持加密")
+	}
+	return vs.Lock()
+}
+
+// ChangePassphrase 更换当前存储后端的加密口令（仅 JSON 文件后端支持）。
+func (cm *ConfigManager) ChangePassphrase(oldPassphrase, newPassphrase string) error {
+	vs, ok := cm.store.(vaultStore)
+	if !ok {
+		return errors.New("当前配置后端不支持加密")
+	}
+	return vs.ChangePassphrase(oldPassphrase, newPassphrase)
+}
@@ -0,0 +1,182 @@
+package remote
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ConfigEventType 标识一次热加载检测到的配置变更的种类。
+type ConfigEventType int
+
+const (
+	ConfigAdded ConfigEventType = iota
+	ConfigUpdated
+	ConfigRemoved
+)
+
+// ConfigEvent 描述热加载检测到的一条配置增删改，由 Subscribe 返回的
+// channel 投递。Removed 事件的 Config 字段为 nil。
+type ConfigEvent struct {
+	Type   ConfigEventType
+	Name   string
+	Config *RemoteConfig
+}
+
+// configFilePather 由能落地成单个文件、因此可以被 fsnotify 监听的
+// ConfigStore 实现（目前是 jsonFileConfigStore/iniConfigStore/
+// yamlConfigStore）。env 和远程 KV 后端没有对应的本地文件，不实现它。
+type configFilePather interface {
+	ConfigFilePath() string
+}
+
+// watchDebounce 合并同一批写入触发的连续 fsnotify 事件：编辑器保存文件
+// 常常产生多个 Write/Create 事件，在这个窗口内的事件只触发一次重新加载。
+const watchDebounce = 200 * time.Millisecond
+
+// Subscribe 启动（如尚未启动）对配置文件的 fsnotify 监听，并返回一个每当
+// 检测到配置被新增、更新或删除时收到 ConfigEvent 的只读 channel。可以多
+// 次调用以注册多个独立订阅者，它们共享同一个底层 watcher。仅当前存储后
+// 端落地成单一文件（JSON/INI/YAML）时才支持，其余后端返回错误。
+func (cm *ConfigManager) Subscribe() (<-chan ConfigEvent, error) {
+	cm.watchMu.Lock()
+	defer cm.watchMu.Unlock()
+
+	ch := make(chan ConfigEvent, 16)
+
+	if cm.watcher != nil {
+		cm.subscribers = append(cm.subscribers, ch)
+		return ch, nil
+	}
+
+	pather, ok := cm.store.(configFilePather)
+	if !ok {
+		close(ch)
+		return nil, fmt.Errorf("当前配置后端不支持热加载")
+	}
+
+	snapshot, err := cm.store.Load()
+	if err != nil {
+		close(ch)
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		close(ch)
+		return nil, fmt.Errorf("创建文件监听器失败: %v", err)
+	}
+	if err := watcher.Add(pather.ConfigFilePath()); err != nil {
+		watcher.Close()
+		close(ch)
+		return nil, fmt.Errorf("监听配置文件失败: %v", err)
+	}
+
+	cm.watcher = watcher
+	cm.watchDone = make(chan struct{})
+	cm.lastSnapshot = snapshot
+	cm.subscribers = append(cm.subscribers, ch)
+
+	go cm.watchLoop()
+
+	return ch, nil
+}
+
+// watchLoop 消费 fsnotify 事件并去抖，真正的重新加载与事件分发交给
+// reloadAndPublish。
+func (cm *ConfigManager) watchLoop() {
+	var debounce *time.Timer
+	defer func() {
+		if debounce != nil {
+			debounce.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-cm.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(watchDebounce, cm.reloadAndPublish)
+			} else {
+				debounce.Reset(watchDebounce)
+			}
+		case _, ok := <-cm.watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-cm.watchDone:
+			return
+		}
+	}
+}
+
+// reloadAndPublish 重新从存储加载配置，与上一次快照逐项比较，把差异当作
+// ConfigEvent 广播给所有订阅者。
+func (cm *ConfigManager) reloadAndPublish() {
+	configs, err := cm.store.Load()
+	if err != nil {
+		return
+	}
+
+	cm.watchMu.Lock()
+	previous := cm.lastSnapshot
+	cm.lastSnapshot = configs
+	subscribers := append([]chan ConfigEvent(nil), cm.subscribers...)
+	cm.watchMu.Unlock()
+
+	for name, config := range configs {
+		old, existed := previous[name]
+		switch {
+		case !existed:
+			publishConfigEvent(subscribers, ConfigEvent{Type: ConfigAdded, Name: name, Config: config})
+		case !reflect.DeepEqual(old, config):
+			publishConfigEvent(subscribers, ConfigEvent{Type: ConfigUpdated, Name: name, Config: config})
+		}
+	}
+	for name := range previous {
+		if _, ok := configs[name]; !ok {
+			publishConfigEvent(subscribers, ConfigEvent{Type: ConfigRemoved, Name: name})
+		}
+	}
+}
+
+// publishConfigEvent 把事件投给每个订阅者；订阅者消费不及时导致 channel
+// 满时丢弃该事件，而不是阻塞整个热加载 goroutine。
+func publishConfigEvent(subscribers []chan ConfigEvent, event ConfigEvent) {
+	for _, ch := range subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close 停止配置文件监听并关闭所有订阅者 channel；在从未调用过 Subscribe
+// 时是无操作。
+func (cm *ConfigManager) Close() error {
+	cm.watchMu.Lock()
+	defer cm.watchMu.Unlock()
+
+	if cm.watcher == nil {
+		return nil
+	}
+
+	close(cm.watchDone)
+	err := cm.watcher.Close()
+
+	for _, ch := range cm.subscribers {
+		close(ch)
+	}
+	cm.subscribers = nil
+	cm.watcher = nil
+
+	return err
+}
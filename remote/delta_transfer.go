@@ -0,0 +1,131 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// SyncFile 对单个远程已存在的文件执行类似 rsync 的增量（delta）传输：
+// 本地文件与远程文件用同一套内容定义分块（CDC）算法切分，只有哈希在
+// 远程侧不存在的分块才会通过 SFTP 真正上传，其余分块由远程侧用 `dd`
+// 直接从旧文件中对应的字节区间复制出来，无需重新过网络。所有分块就绪
+// 后在远程侧原子地拼接覆盖 remotePath。
+//
+// 这对大文件的小范围编辑（日志、数据库转储、构建产物等频繁编辑的大
+// 文件）能显著减少上传字节数，与 CalculateDifferences 判断"哪些文件
+// 需要更新"是互补关系：后者决定*是否*要传这个文件，SyncFile 决定
+// *怎么*传得更省。
+func (c *SSHClient) SyncFile(sftp *SFTPSubsystem, localPath, remotePath string) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+
+	localData, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("读取本地文件失败 %s: %v", localPath, err)
+	}
+	localChunks := chunkData(localData)
+
+	remoteChunks, err := c.remoteChunks(sftp, remotePath)
+	if err != nil {
+		return fmt.Errorf("读取远程文件分块失败: %v", err)
+	}
+
+	remoteByHash := make(map[string]Chunk, len(remoteChunks))
+	for _, rc := range remoteChunks {
+		if _, exists := remoteByHash[rc.Hash]; !exists {
+			remoteByHash[rc.Hash] = rc
+		}
+	}
+
+	stagingDir := deltaStagingDir(remotePath)
+	if err := sftp.Client().MkdirAll(stagingDir); err != nil {
+		return fmt.Errorf("创建增量传输暂存目录失败 %s: %v", stagingDir, err)
+	}
+
+	chunkPaths := make([]string, len(localChunks))
+
+	for i, chunk := range localChunks {
+		chunkPath := path.Join(stagingDir, fmt.Sprintf("chunk.%d", i))
+		chunkPaths[i] = chunkPath
+
+		if remoteChunk, ok := remoteByHash[chunk.Hash]; ok {
+			if err := c.copyRemoteRange(remotePath, chunkPath, remoteChunk.Offset, remoteChunk.Length); err != nil {
+				return fmt.Errorf("服务端复用分块 %d 失败: %v", i, err)
+			}
+			continue
+		}
+
+		remoteFile, err := sftp.Client().Create(chunkPath)
+		if err != nil {
+			return fmt.Errorf("创建远程分块文件失败 %s: %v", chunkPath, err)
+		}
+		if _, err := remoteFile.Write(chunk.Data); err != nil {
+			remoteFile.Close()
+			return fmt.Errorf("上传分块 %d 失败: %v", i, err)
+		}
+		remoteFile.Close()
+	}
+
+	return c.assembleDeltaFile(stagingDir, remotePath, chunkPaths)
+}
+
+// remoteChunks 读取远程文件的全部内容并用与本地相同的 CDC 算法分块，
+// 从而得到远程侧的分块哈希列表。远程文件不存在时返回空列表（等价于
+// 整份增量上传）。
+func (c *SSHClient) remoteChunks(sftp *SFTPSubsystem, remotePath string) ([]Chunk, error) {
+	remoteFile, err := sftp.Client().Open(remotePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	data, err := io.ReadAll(remoteFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取远程文件失败 %s: %v", remotePath, err)
+	}
+
+	return chunkData(data), nil
+}
+
+// copyRemoteRange 在远程侧通过 dd 把 sourcePath 中 [offset, offset+length)
+// 的字节区间复制到 destPath，用于复用双方都已拥有的内容而不必重新
+// 经网络传输。
+func (c *SSHClient) copyRemoteRange(sourcePath, destPath string, offset int64, length int) error {
+	command := fmt.Sprintf("dd if='%s' of='%s' bs=1 skip=%d count=%d 2>/dev/null",
+		sourcePath, destPath, offset, length)
+	_, err := c.ExecuteCommand(command)
+	return err
+}
+
+// assembleDeltaFile 将所有分块（无论是新上传的还是服务端复用的）按序
+// 拼接成最终文件，原子替换 remotePath，并清理暂存目录。
+func (c *SSHClient) assembleDeltaFile(stagingDir, remotePath string, chunkPaths []string) error {
+	quoted := make([]string, len(chunkPaths))
+	for i, p := range chunkPaths {
+		quoted[i] = fmt.Sprintf("'%s'", p)
+	}
+
+	tmpPath := remotePath + ".delta-tmp"
+	command := fmt.Sprintf("cat %s > '%s' && mv '%s' '%s' && rm -rf '%s'",
+		strings.Join(quoted, " "), tmpPath, tmpPath, remotePath, stagingDir)
+
+	if _, err := c.ExecuteCommand(command); err != nil {
+		return fmt.Errorf("远程拼接增量分块失败: %v", err)
+	}
+	return nil
+}
+
+// deltaStagingDir 计算某个远程目标文件对应的增量传输暂存目录。
+func deltaStagingDir(remotePath string) string {
+	id := sha256.Sum256([]byte(remotePath))
+	return path.Join(path.Dir(remotePath), ".delta", hex.EncodeToString(id[:]))
+}
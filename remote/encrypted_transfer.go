@@ -0,0 +1,185 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"synccli/bindings"
+)
+
+// backendMetadataSetter 是 Backend 的可选扩展：内容写完之后能回填权限与
+// 修改时间。目前只有 *SSHClient（基于 SFTP）实现了它；S3/WebDAV 等 Backend
+// 没有对等概念，类型断言失败时直接跳过，只保证内容本身被正确写入。
+type backendMetadataSetter interface {
+	Chmod(path string, mode os.FileMode) error
+	Chtimes(path string, modTime time.Time) error
+}
+
+// uploadEncrypted 把本地文件流式加密后写入远程：本地文件 -> EncryptStream ->
+// backend.Create 返回的句柄，全程常量内存占用，本地和远程都不落地明文/密文
+// 的中间副本。只有 config.Encryption 为真且调用方提供了非空
+// EncryptionPassphrase 时，runSyncItem 才会走到这里；加密后的内容直接覆盖
+// remotePath，解密侧（downloadEncrypted）按同一条路径读回。
+func (rse *RemoteSyncEngine) uploadEncrypted(backend Backend, localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	info, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败 %s: %v", localPath, err)
+	}
+
+	if err := backend.Mkdir(path.Dir(remotePath)); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", path.Dir(remotePath), err)
+	}
+
+	remoteFile, err := backend.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+
+	encErr := bindings.EncryptStream(localFile, remoteFile, []byte(rse.options.EncryptionPassphrase), bindings.DefaultKDFParams())
+	if closeErr := remoteFile.Close(); encErr == nil {
+		encErr = closeErr
+	}
+	if encErr != nil {
+		return fmt.Errorf("流式加密上传失败 %s: %v", remotePath, encErr)
+	}
+
+	if setter, ok := backend.(backendMetadataSetter); ok {
+		if err := setter.Chmod(remotePath, info.Mode()); err != nil {
+			return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+		}
+		if err := setter.Chtimes(remotePath, info.ModTime()); err != nil {
+			return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+		}
+	}
+
+	return nil
+}
+
+// downloadEncrypted 是 uploadEncrypted 的对称版本：远程文件 -> DecryptStream
+// -> 本地文件，同样全程流式处理，不在内存中保留整份明文或密文。
+func (rse *RemoteSyncEngine) downloadEncrypted(backend Backend, remotePath, localPath string) error {
+	remoteFile, err := backend.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	info, err := backend.Stat(remotePath)
+	if err != nil {
+		return fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败 %s: %v", filepath.Dir(localPath), err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败 %s: %v", localPath, err)
+	}
+
+	decErr := bindings.DecryptStream(remoteFile, localFile, []byte(rse.options.EncryptionPassphrase), bindings.DefaultKDFParams())
+	if closeErr := localFile.Close(); decErr == nil {
+		decErr = closeErr
+	}
+	if decErr != nil {
+		return fmt.Errorf("流式解密下载失败 %s: %v", localPath, decErr)
+	}
+
+	if err := os.Chtimes(localPath, info.ModTime, info.ModTime); err != nil {
+		return fmt.Errorf("设置本地文件时间失败 %s: %v", localPath, err)
+	}
+
+	return nil
+}
+
+// uploadPlain 是 uploadEncrypted 的非加密版本：本地文件内容直接流式拷贝
+// 到 backend.Create 返回的句柄，不经过 EncryptStream。executeSyncPlanBackend
+// 在 config.Encryption 未开启（或未提供口令）时走这条路径。
+func (rse *RemoteSyncEngine) uploadPlain(backend Backend, localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	info, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败 %s: %v", localPath, err)
+	}
+
+	if err := backend.Mkdir(path.Dir(remotePath)); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", path.Dir(remotePath), err)
+	}
+
+	remoteFile, err := backend.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+
+	_, copyErr := io.Copy(remoteFile, localFile)
+	if closeErr := remoteFile.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		return fmt.Errorf("上传失败 %s: %v", remotePath, copyErr)
+	}
+
+	if setter, ok := backend.(backendMetadataSetter); ok {
+		if err := setter.Chmod(remotePath, info.Mode()); err != nil {
+			return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+		}
+		if err := setter.Chtimes(remotePath, info.ModTime()); err != nil {
+			return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+		}
+	}
+
+	return nil
+}
+
+// downloadPlain 是 downloadEncrypted 的非加密版本。
+func (rse *RemoteSyncEngine) downloadPlain(backend Backend, remotePath, localPath string) error {
+	remoteFile, err := backend.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	info, err := backend.Stat(remotePath)
+	if err != nil {
+		return fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败 %s: %v", filepath.Dir(localPath), err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败 %s: %v", localPath, err)
+	}
+
+	_, copyErr := io.Copy(localFile, remoteFile)
+	if closeErr := localFile.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		return fmt.Errorf("下载失败 %s: %v", localPath, copyErr)
+	}
+
+	if err := os.Chtimes(localPath, info.ModTime, info.ModTime); err != nil {
+		return fmt.Errorf("设置本地文件时间失败 %s: %v", localPath, err)
+	}
+
+	return nil
+}
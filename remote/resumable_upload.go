@@ -0,0 +1,175 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// BlockManifest 记录一次断点续传的分块布局与每个分块的校验和，是最后一个
+// 上传到暂存目录的文件，拼接成功后随暂存目录一起删除。重试时不读取它：
+// 哪些分块已经完成由 remoteBlockHash 对每个暂存分块重新计算哈希来判断。
+type BlockManifest struct {
+	RemotePath  string   `json:"remotePath"`
+	Size        int64    `json:"size"`
+	BlockSize   int64    `json:"blockSize"`
+	BlockHashes []string `json:"blockHashes"`
+}
+
+// uploadResumable 以分块方式上传大文件，支持中断后从未完成/校验失败的
+// 分块处继续，而不是像 SFTPSubsystem.Upload 那样每次都从零开始。
+//
+// 分块先写入远程 `.partial/<sha256(remotePath)>/block.<index>` 暂存区，
+// 已存在且哈希一致的分块被跳过，其余分块重新上传（上传后不再回读校验）；
+// 分块清单最后上传，全部分块就绪后，在远程侧原子地拼接成最终文件。
+func (rse *RemoteSyncEngine) uploadResumable(sftp *SFTPSubsystem, localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	info, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败 %s: %v", localPath, err)
+	}
+
+	blockSize := rse.options.BlockSize
+	totalSize := info.Size()
+	numBlocks := int((totalSize + blockSize - 1) / blockSize)
+	if numBlocks == 0 {
+		numBlocks = 1
+	}
+
+	stagingDir := resumeStagingDir(remotePath)
+	if err := sftp.Mkdir(stagingDir); err != nil {
+		return fmt.Errorf("创建断点续传暂存目录失败 %s: %v", stagingDir, err)
+	}
+
+	blockHashes := make([]string, numBlocks)
+	buf := make([]byte, blockSize)
+
+	for i := 0; i < numBlocks; i++ {
+		n, readErr := io.ReadFull(localFile, buf)
+		if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+			return fmt.Errorf("读取分块 %d 失败: %v", i, readErr)
+		}
+		block := buf[:n]
+		sum := sha256.Sum256(block)
+		hash := hex.EncodeToString(sum[:])
+		blockHashes[i] = hash
+
+		remoteBlockPath := path.Join(stagingDir, fmt.Sprintf("block.%d", i))
+
+		existingHash, _ := rse.remoteBlockHash(sftp, remoteBlockPath)
+		if existingHash == hash {
+			if rse.options.Verbose {
+				fmt.Printf("分块 %d 已存在且校验通过，跳过\n", i)
+			}
+			continue
+		}
+
+		if err := rse.uploadBlockWithRetry(sftp, remoteBlockPath, block, rse.options.ResumeRetry); err != nil {
+			return fmt.Errorf("分块 %d 上传失败（已重试 %d 次）: %v", i, rse.options.ResumeRetry, err)
+		}
+	}
+
+	manifest := BlockManifest{
+		RemotePath:  remotePath,
+		Size:        totalSize,
+		BlockSize:   blockSize,
+		BlockHashes: blockHashes,
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return fmt.Errorf("序列化分块清单失败: %v", err)
+	}
+
+	manifestPath := path.Join(stagingDir, "manifest.json")
+	if err := rse.uploadBytes(sftp, manifestPath, manifestData); err != nil {
+		return fmt.Errorf("上传分块清单失败: %v", err)
+	}
+
+	return rse.assembleRemoteFile(sftp, stagingDir, remotePath, numBlocks, info.Mode(), info.ModTime())
+}
+
+// resumeStagingDir 计算某个远程目标文件对应的分块暂存目录。
+func resumeStagingDir(remotePath string) string {
+	id := sha256.Sum256([]byte(remotePath))
+	return path.Join(path.Dir(remotePath), ".partial", hex.EncodeToString(id[:]))
+}
+
+// remoteBlockHash 通过远程执行的小型哈希命令校验已上传分块的完整性，
+// 不存在或无法读取时返回空字符串。
+func (rse *RemoteSyncEngine) remoteBlockHash(sftp *SFTPSubsystem, remoteBlockPath string) (string, error) {
+	command := fmt.Sprintf("sha256sum '%s' 2>/dev/null | awk '{print $1}'", remoteBlockPath)
+	output, err := rse.sshClient.ExecuteCommand(command)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// uploadBlockWithRetry 上传单个分块，失败时按指数退避重试最多 maxRetry 次。
+func (rse *RemoteSyncEngine) uploadBlockWithRetry(sftp *SFTPSubsystem, remoteBlockPath string, data []byte, maxRetry int) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetry; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			time.Sleep(backoff)
+		}
+
+		if err := rse.uploadBytes(sftp, remoteBlockPath, data); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// uploadBytes 将内存中的字节切片写入远程路径。
+func (rse *RemoteSyncEngine) uploadBytes(sftp *SFTPSubsystem, remotePath string, data []byte) error {
+	remoteFile, err := sftp.Client().Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	if _, err := remoteFile.Write(data); err != nil {
+		return fmt.Errorf("写入远程文件失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// assembleRemoteFile 在全部分块就绪后，于远程侧原子地拼接出最终文件并清理暂存目录，
+// 再按本地源文件的权限与修改时间设置目标文件，与 SFTPSubsystem.Upload 的非断点续传
+// 路径保持一致，避免大文件走断点续传时悄悄丢失 mode/mtime。
+func (rse *RemoteSyncEngine) assembleRemoteFile(sftp *SFTPSubsystem, stagingDir, remotePath string, numBlocks int, mode os.FileMode, modTime time.Time) error {
+	blockPaths := make([]string, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		blockPaths[i] = fmt.Sprintf("'%s'", path.Join(stagingDir, fmt.Sprintf("block.%d", i)))
+	}
+
+	tmpPath := remotePath + ".resume-tmp"
+	command := fmt.Sprintf("cat %s > '%s' && mv '%s' '%s' && rm -rf '%s'",
+		strings.Join(blockPaths, " "), tmpPath, tmpPath, remotePath, stagingDir)
+
+	if _, err := rse.sshClient.ExecuteCommand(command); err != nil {
+		return fmt.Errorf("远程拼接分块失败: %v", err)
+	}
+
+	if err := sftp.Client().Chmod(remotePath, mode); err != nil {
+		return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+	}
+	if err := sftp.Client().Chtimes(remotePath, modTime, modTime); err != nil {
+		return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
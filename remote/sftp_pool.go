@@ -0,0 +1,284 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/pkg/sftp"
+)
+
+// parallelStreamThreshold 是触发多流并发传输的文件大小下限：小文件走
+// 单连接整份传输更划算，分流的握手/调度开销反而更高。
+const parallelStreamThreshold = 32 * 1024 * 1024 // 32 MiB
+
+// defaultParallelStreams 是 UploadFile/DownloadFile 委托多流传输时
+// 默认使用的并发流数。
+const defaultParallelStreams = 4
+
+// streamRange 是 UploadFileParallel/DownloadFileParallel 中单个并发流
+// 负责的字节区间。
+type streamRange struct {
+	offset int64
+	length int64
+}
+
+// splitStreamRanges 把总长度为 size 的文件切成最多 streams 段大致
+// 相等的字节区间，供各并发流各自用 WriteAt/ReadAt 处理。
+func splitStreamRanges(size int64, streams int) []streamRange {
+	if streams < 1 {
+		streams = 1
+	}
+	if int64(streams) > size {
+		streams = int(size)
+	}
+	if streams < 1 {
+		streams = 1
+	}
+
+	chunkSize := size / int64(streams)
+	ranges := make([]streamRange, 0, streams)
+	offset := int64(0)
+	for i := 0; i < streams; i++ {
+		length := chunkSize
+		if i == streams-1 {
+			length = size - offset
+		}
+		if length <= 0 {
+			continue
+		}
+		ranges = append(ranges, streamRange{offset: offset, length: length})
+		offset += length
+	}
+	return ranges
+}
+
+// UploadFileParallel 对单个大文件开 streams 条并发 SFTP 会话，每条会话
+// 用 sftp.File.WriteAt 只写目标文件中互不重叠的一段字节区间，像多流
+// scp/HPN-SSH 那样压满高带宽时延积（BDP）链路。任意一段写入失败都会
+// 中止其余尚未开始的段并返回该错误。
+func (c *SSHClient) UploadFileParallel(localPath, remotePath string, streams int) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败 %s: %v", localPath, err)
+	}
+
+	remoteDir := path.Dir(remotePath)
+	if err := c.sftpClient.MkdirAll(remoteDir); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", remoteDir, err)
+	}
+
+	remoteFile, err := c.sftpClient.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+	if err := remoteFile.Truncate(info.Size()); err != nil {
+		remoteFile.Close()
+		return fmt.Errorf("预分配远程文件大小失败 %s: %v", remotePath, err)
+	}
+	remoteFile.Close()
+
+	bar := pb.StartNew(int(info.Size()))
+	defer bar.Finish()
+
+	err = c.runRangesParallel(splitStreamRanges(info.Size(), streams), func(client *sftp.Client, r streamRange) error {
+		return uploadStreamRange(client, localPath, remotePath, r, bar)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.sftpClient.Chmod(remotePath, info.Mode()); err != nil {
+		return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+	}
+	if err := c.sftpClient.Chtimes(remotePath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+	}
+
+	return nil
+}
+
+// DownloadFileParallel 是 UploadFileParallel 的下载对称版本：开 streams
+// 条并发 SFTP 会话，每条用 sftp.File.ReadAt 读取源文件互不重叠的一段
+// 字节区间，写入本地文件对应的偏移。
+func (c *SSHClient) DownloadFileParallel(remotePath, localPath string, streams int) error {
+	if !c.connected {
+		return fmt.Errorf("ssh未连接")
+	}
+
+	info, err := c.sftpClient.Stat(remotePath)
+	if err != nil {
+		return fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+
+	localDir := filepath.Dir(localPath)
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败 %s: %v", localDir, err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败 %s: %v", localPath, err)
+	}
+	if err := localFile.Truncate(info.Size()); err != nil {
+		localFile.Close()
+		return fmt.Errorf("预分配本地文件大小失败 %s: %v", localPath, err)
+	}
+	localFile.Close()
+
+	bar := pb.StartNew(int(info.Size()))
+	defer bar.Finish()
+
+	err = c.runRangesParallel(splitStreamRanges(info.Size(), streams), func(client *sftp.Client, r streamRange) error {
+		return downloadStreamRange(client, remotePath, localPath, r, bar)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(localPath, info.Mode()); err != nil {
+		return fmt.Errorf("设置本地文件权限失败 %s: %v", localPath, err)
+	}
+	if err := os.Chtimes(localPath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("设置本地文件时间失败 %s: %v", localPath, err)
+	}
+
+	return nil
+}
+
+// runRangesParallel 为每个字节区间各开一条独立的 SFTP 会话并发执行 do，
+// 首个错误通过取消 context 中止尚未开始的区间（error-group 语义）。
+func (c *SSHClient) runRangesParallel(ranges []streamRange, do func(client *sftp.Client, r streamRange) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	var wg sync.WaitGroup
+
+	for _, r := range ranges {
+		wg.Add(1)
+		go func(r streamRange) {
+			defer wg.Done()
+
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			session, sessionErr := c.NewSFTPSession()
+			if sessionErr != nil {
+				select {
+				case errCh <- fmt.Errorf("创建并发传输会话失败: %v", sessionErr):
+				default:
+				}
+				cancel()
+				return
+			}
+			defer session.Client().Close()
+
+			if err := do(session.Client(), r); err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				cancel()
+			}
+		}(r)
+	}
+
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+// uploadStreamRange 把本地文件 [r.offset, r.offset+r.length) 区间的内容
+// 通过 client 写入远程文件的同一区间。
+func uploadStreamRange(client *sftp.Client, localPath, remotePath string, r streamRange, bar *pb.ProgressBar) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	remoteFile, err := client.OpenFile(remotePath, os.O_WRONLY)
+	if err != nil {
+		return fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	section := io.NewSectionReader(localFile, r.offset, r.length)
+	buf := make([]byte, 256*1024)
+	offset := r.offset
+
+	for {
+		n, readErr := section.Read(buf)
+		if n > 0 {
+			if _, err := remoteFile.WriteAt(buf[:n], offset); err != nil {
+				return fmt.Errorf("写入远程区间失败 %s @%d: %v", remotePath, offset, err)
+			}
+			offset += int64(n)
+			bar.Add(n)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("读取本地区间失败 %s @%d: %v", localPath, offset, readErr)
+		}
+	}
+
+	return nil
+}
+
+// downloadStreamRange 把远程文件 [r.offset, r.offset+r.length) 区间的
+// 内容通过 client 读取出来，写入本地文件的同一区间。
+func downloadStreamRange(client *sftp.Client, remotePath, localPath string, r streamRange, bar *pb.ProgressBar) error {
+	remoteFile, err := client.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	localFile, err := os.OpenFile(localPath, os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	section := io.NewSectionReader(remoteFile, r.offset, r.length)
+	buf := make([]byte, 256*1024)
+	offset := r.offset
+
+	for {
+		n, readErr := section.Read(buf)
+		if n > 0 {
+			if _, err := localFile.WriteAt(buf[:n], offset); err != nil {
+				return fmt.Errorf("写入本地区间失败 %s @%d: %v", localPath, offset, err)
+			}
+			offset += int64(n)
+			bar.Add(n)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("读取远程区间失败 %s @%d: %v", remotePath, offset, readErr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/kr/fs"
+	"github.com/pkg/sftp"
+)
+
+// SFTPSubsystem 是基于 pkg/sftp 构建的一等文件传输子系统，
+// 取代此前通过 shell 调用 scp/find/rm 的脆弱实现。
+type SFTPSubsystem struct {
+	client *sftp.Client
+	bar    *pb.ProgressBar
+}
+
+// NewSFTPSubsystem 基于已建立的 sftp.Client 创建子系统。
+func NewSFTPSubsystem(client *sftp.Client) *SFTPSubsystem {
+	return &SFTPSubsystem{client: client}
+}
+
+// SetProgressBar 设置用于按字节汇报进度的进度条，传 nil 可关闭进度汇报。
+func (s *SFTPSubsystem) SetProgressBar(bar *pb.ProgressBar) {
+	s.bar = bar
+}
+
+// Client 返回底层的 sftp.Client，供需要直接操作的调用方使用。
+func (s *SFTPSubsystem) Client() *sftp.Client {
+	return s.client
+}
+
+// Upload 将本地文件流式上传到远程路径，并保留文件权限与修改时间。
+func (s *SFTPSubsystem) Upload(localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	info, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败 %s: %v", localPath, err)
+	}
+
+	if err := s.client.MkdirAll(path.Dir(remotePath)); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", path.Dir(remotePath), err)
+	}
+
+	remoteFile, err := s.client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("创建远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	var reader io.Reader = localFile
+	if s.bar != nil {
+		reader = s.bar.NewProxyReader(localFile)
+	}
+
+	if _, err := io.Copy(remoteFile, reader); err != nil {
+		return fmt.Errorf("上传文件内容失败 %s: %v", remotePath, err)
+	}
+
+	if err := s.client.Chmod(remotePath, info.Mode()); err != nil {
+		return fmt.Errorf("设置远程文件权限失败 %s: %v", remotePath, err)
+	}
+	if err := s.client.Chtimes(remotePath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("设置远程文件时间失败 %s: %v", remotePath, err)
+	}
+
+	return nil
+}
+
+// Download 将远程文件流式下载到本地路径，并保留文件权限与修改时间。
+func (s *SFTPSubsystem) Download(remotePath, localPath string) error {
+	remoteFile, err := s.client.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("打开远程文件失败 %s: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	info, err := remoteFile.Stat()
+	if err != nil {
+		return fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败 %s: %v", filepath.Dir(localPath), err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败 %s: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	var reader io.Reader = remoteFile
+	if s.bar != nil {
+		reader = s.bar.NewProxyReader(remoteFile)
+	}
+
+	if _, err := io.Copy(localFile, reader); err != nil {
+		return fmt.Errorf("下载文件内容失败 %s: %v", localPath, err)
+	}
+	localFile.Close()
+
+	if err := os.Chmod(localPath, info.Mode()); err != nil {
+		return fmt.Errorf("设置本地文件权限失败 %s: %v", localPath, err)
+	}
+	if err := os.Chtimes(localPath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("设置本地文件时间失败 %s: %v", localPath, err)
+	}
+
+	return nil
+}
+
+// UploadDir 递归上传本地目录到远程目录。
+//
+// 目的地解析遵循 rsync 风格的结尾斜杠语义：localDir 以分隔符结尾时
+// （如 "src/"），只同步其内容到 remoteDir；不以分隔符结尾时（"src"），
+// 在 remoteDir 下新建一层以 localDir 基名命名的目录。
+func (s *SFTPSubsystem) UploadDir(localDir, remoteDir string) error {
+	remoteRoot := resolveUploadRoot(localDir, remoteDir)
+
+	return filepath.WalkDir(localDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		remotePath := remoteRoot
+		if rel != "." {
+			remotePath = path.Join(remoteRoot, filepath.ToSlash(rel))
+		}
+
+		if d.IsDir() {
+			return s.Mkdir(remotePath)
+		}
+		return s.Upload(p, remotePath)
+	})
+}
+
+// DownloadDir 递归下载远程目录到本地目录，结尾斜杠语义与 UploadDir 对称。
+func (s *SFTPSubsystem) DownloadDir(remoteDir, localDir string) error {
+	localRoot := resolveDownloadRoot(remoteDir, localDir)
+
+	walker := s.Walk(remoteDir)
+	for walker.Step() {
+		if walker.Err() != nil {
+			return fmt.Errorf("遍历远程目录失败 %s: %v", remoteDir, walker.Err())
+		}
+
+		rel := strings.TrimPrefix(walker.Path(), remoteDir)
+		rel = strings.TrimPrefix(rel, "/")
+		localPath := localRoot
+		if rel != "" {
+			localPath = filepath.Join(localRoot, filepath.FromSlash(rel))
+		}
+
+		if walker.Stat().IsDir() {
+			if err := os.MkdirAll(localPath, 0755); err != nil {
+				return fmt.Errorf("创建本地目录失败 %s: %v", localPath, err)
+			}
+			continue
+		}
+		if err := s.Download(walker.Path(), localPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveUploadRoot 计算上传时的远程根目录，实现 rsync 式结尾斜杠语义。
+func resolveUploadRoot(localDir, remoteDir string) string {
+	if strings.HasSuffix(localDir, "/") || strings.HasSuffix(localDir, string(filepath.Separator)) {
+		return remoteDir
+	}
+	return path.Join(remoteDir, filepath.Base(filepath.Clean(localDir)))
+}
+
+// resolveDownloadRoot 计算下载时的本地根目录，实现 rsync 式结尾斜杠语义。
+func resolveDownloadRoot(remoteDir, localDir string) string {
+	if strings.HasSuffix(remoteDir, "/") {
+		return localDir
+	}
+	return filepath.Join(localDir, path.Base(path.Clean(remoteDir)))
+}
+
+// Remove 删除远程文件。
+func (s *SFTPSubsystem) Remove(remotePath string) error {
+	if err := s.client.Remove(remotePath); err != nil {
+		return fmt.Errorf("删除远程文件失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Mkdir 递归创建远程目录，已存在时不报错。
+func (s *SFTPSubsystem) Mkdir(remotePath string) error {
+	if err := s.client.MkdirAll(remotePath); err != nil {
+		return fmt.Errorf("创建远程目录失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Stat 获取远程路径的文件信息。
+func (s *SFTPSubsystem) Stat(remotePath string) (os.FileInfo, error) {
+	info, err := s.client.Stat(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+	return info, nil
+}
+
+// Walk 返回一个从 remoteRoot 开始的远程目录遍历器，用于替代原先基于
+// `find -printf` 的解析逻辑（该逻辑在 BSD/macOS 上以及路径含制表符/换行符时会出错）。
+func (s *SFTPSubsystem) Walk(remoteRoot string) *fs.Walker {
+	return s.client.Walk(remoteRoot)
+}
@@ -17,14 +17,37 @@ import (
 
 // ssh连接配置
 type SSHConfig struct {
-	Host            string `json:"host"`
-	Port            int    `json:"port"`
-	Username        string `json:"username"`
-	Password        string `json:"password"`
-	KeyFile         string `json:"keyFile"`
-	Timeout         int    `json:"timeout"`
-	KnownHostsFile  string `json:"knownHostsFile"`
-	StrictHostCheck bool   `json:"strictHostCheck"`
+	Host            string `json:"host" yaml:"host"`
+	Port            int    `json:"port" yaml:"port"`
+	Username        string `json:"username" yaml:"username"`
+	Password        string `json:"password" yaml:"password"`
+	KeyFile         string `json:"keyFile" yaml:"keyFile"`
+	Timeout         int    `json:"timeout" yaml:"timeout"`
+	KnownHostsFile  string `json:"knownHostsFile" yaml:"knownHostsFile"`
+	StrictHostCheck bool   `json:"strictHostCheck" yaml:"strictHostCheck"`
+
+	// HostKeyPrompt 在遇到 known_hosts 中没有记录的未知主机时被调用，由
+	// 调用方决定是否信任本次连接（accept）以及是否把该主机密钥写入
+	// known_hosts 供以后免提示（persist）。为 nil 时未知主机一律拒绝
+	// 连接，即保持 StrictHostCheck 本来的严格语义。不参与 JSON/YAML 序列化。
+	HostKeyPrompt HostKeyPrompt `json:"-" yaml:"-"`
+}
+
+// HostKeyPrompt 是未知主机密钥的信任决策回调。
+type HostKeyPrompt func(UnknownHostError) (accept bool, persist bool, err error)
+
+// UnknownHostError 描述一次 TOFU（Trust On First Use）场景：known_hosts
+// 中没有该主机的记录，调用方需要凭指纹自行判断是否信任。
+type UnknownHostError struct {
+	Hostname    string
+	RemoteAddr  string
+	KeyType     string
+	Fingerprint string // SHA256 格式指纹，与 `ssh-keygen -lf` 输出一致
+}
+
+func (e UnknownHostError) Error() string {
+	return fmt.Sprintf("未知主机 %s (%s)：%s 密钥指纹 %s，无法验证其真实性",
+		e.Hostname, e.RemoteAddr, e.KeyType, e.Fingerprint)
 }
 
 // ssh客户端
@@ -32,6 +55,7 @@ type SSHClient struct {
 	config     *SSHConfig
 	sshClient  *ssh.Client
 	sftpClient *sftp.Client
+	subsystem  *SFTPSubsystem
 	connected  bool
 }
 
@@ -56,27 +80,27 @@ func (c *SSHClient) Connect() error {
 	}
 
 	sshConfig := &ssh.ClientConfig{
-		user:    c.config.Username,
+		User:    c.config.Username,
 		Timeout: time.Duration(c.config.Timeout) * time.Second,
 	}
 
-	// 主机迷药验证回调
+	// 主机密钥验证回调
 	hostKeyCallback, err := c.createHostKeyCallback()
 	if err != nil {
-		return fmt.Errorf("Errorf: %v", err)
+		return fmt.Errorf("创建主机密钥验证回调失败: %v", err)
 	}
 	sshConfig.HostKeyCallback = hostKeyCallback
 
 	// 添加认证方法
 	if err := c.addAuthMethods(sshConfig); err != nil {
-		return fmt.Errorf("Errorf: %v", err)
+		return fmt.Errorf("配置认证方法失败: %v", err)
 	}
 
 	// 建立ssh
-	addr := fmt.Sprintf("%s%d", c.config.Host, c.config.Port)
+	addr := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
 	sshClient, err := ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
-		return fmt.Errorf("ssh Errorf: %v", err)
+		return fmt.Errorf("ssh连接失败: %v", err)
 	}
 
 	c.sshClient = sshClient
@@ -85,14 +109,35 @@ func (c *SSHClient) Connect() error {
 	sftpClient, err := sftp.NewClient(sshClient)
 	if err != nil {
 		c.sshClient.Close()
-		return fmt.Errorf("Errorf: %v", err)
+		return fmt.Errorf("创建SFTP客户端失败: %v", err)
 	}
 
 	c.sftpClient = sftpClient
+	c.subsystem = NewSFTPSubsystem(sftpClient)
 	c.connected = true
 	return nil
 }
 
+// SFTP 返回基于当前连接的 SFTP 子系统，用于文件传输与远程目录遍历。
+func (c *SSHClient) SFTP() *SFTPSubsystem {
+	return c.subsystem
+}
+
+// NewSFTPSession 在共享的 SSH 连接上打开一个独立的 SFTP 会话（通道），
+// 供并发 worker 各自持有，避免多个 worker 争用同一个 SFTP 子系统。
+func (c *SSHClient) NewSFTPSession() (*SFTPSubsystem, error) {
+	if !c.connected {
+		return nil, fmt.Errorf("ssh未连接")
+	}
+
+	sftpClient, err := sftp.NewClient(c.sshClient)
+	if err != nil {
+		return nil, fmt.Errorf("创建SFTP会话失败: %v", err)
+	}
+
+	return NewSFTPSubsystem(sftpClient), nil
+}
+
 // 创建主机密钥验证回调
 func (c *SSHClient) createHostKeyCallback() (ssh.HostKeyCallback, error) {
 	if !c.config.StrictHostCheck {
@@ -103,20 +148,20 @@ func (c *SSHClient) createHostKeyCallback() (ssh.HostKeyCallback, error) {
 	if knownHostsFile == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("Errorf: %v", err)
+			return nil, fmt.Errorf("获取用户主目录失败: %v", err)
 		}
 		knownHostsFile = filepath.Join(homeDir, ".ssh", "known_hosts")
 	}
 
 	if _, err := os.Stat(knownHostsFile); os.IsNotExist(err) {
 		if err := c.createKnownHostsFile(knownHostsFile); err != nil {
-			return nil, fmt.Errorf("Errorf: %v", err)
+			return nil, fmt.Errorf("创建known_hosts文件失败: %v", err)
 		}
 	}
 
 	hostKeyCallback, err := knownhosts.New(knownHostsFile)
 	if err != nil {
-		return nil, fmt.Errorf("Errorf: %v", err)
+		return nil, fmt.Errorf("加载known_hosts文件失败: %v", err)
 	}
 
 	return c.wrapHostKeyCallback(hostKeyCallback, knownHostsFile), nil
@@ -139,18 +184,58 @@ func (c *SSHClient) createKnownHostsFile(filePath string) error {
 	return os.Chmod(filePath, 0600)
 }
 
-// 包装主机密钥验证回调以处理未知主机
+// 包装主机密钥验证回调：
+//
+//   - known_hosts 中记录的密钥与对方提供的不一致（knownhosts.KeyError 且
+//     Want 非空）：判定为密钥变更，可能是中间人攻击（MITM），直接拒绝连接，
+//     不给任何"自动信任"的机会，这与 OpenSSH 的行为一致。
+//   - known_hosts 中完全没有该主机的记录（knownhosts.KeyError 且 Want
+//     为空，即 IsHostUnknown）：这是 TOFU（Trust On First Use）场景，
+//     构造 UnknownHostError 交给 c.config.HostKeyPrompt 决策，而不是像
+//     过去那样不经确认就静默写入 known_hosts——那样做会让 StrictHostCheck
+//     形同虚设。HostKeyPrompt 为 nil 时视为没有可用的确认渠道，直接拒绝。
 func (c *SSHClient) wrapHostKeyCallback(callback ssh.HostKeyCallback, knownHostsFile string) ssh.HostKeyCallback {
 	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
 		err := callback(hostname, remote, key)
-		if err != nil {
-			if strings.Contains(err.Errorf(), "no hostkey found") {
-				if addErr := c.addHostKey(knownHostsFile, hostname, key); addErr != nil {
-					return fmt.Errorf("Errorf: %v", addErr)
-				}
-				return err
+		if err == nil {
+			return nil
+		}
+
+		keyErr, ok := err.(*knownhosts.KeyError)
+		if !ok {
+			return err
+		}
+
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf("主机密钥已变更，可能遭遇中间人攻击（MITM）: %s (%s) 当前指纹 %s，与known_hosts记录不符，拒绝连接",
+				hostname, remote.String(), ssh.FingerprintSHA256(key))
+		}
+
+		unknownErr := UnknownHostError{
+			Hostname:    hostname,
+			RemoteAddr:  remote.String(),
+			KeyType:     key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+		}
+
+		if c.config.HostKeyPrompt == nil {
+			return fmt.Errorf("%w（未配置HostKeyPrompt，无法确认是否信任）", unknownErr)
+		}
+
+		accept, persist, promptErr := c.config.HostKeyPrompt(unknownErr)
+		if promptErr != nil {
+			return fmt.Errorf("主机密钥确认失败: %v", promptErr)
+		}
+		if !accept {
+			return fmt.Errorf("用户拒绝信任主机密钥: %s", unknownErr.Error())
+		}
+
+		if persist {
+			if addErr := c.addHostKey(knownHostsFile, hostname, key); addErr != nil {
+				return fmt.Errorf("写入known_hosts失败: %v", addErr)
 			}
 		}
+
 		return nil
 	}
 }
@@ -163,9 +248,8 @@ func (c *SSHClient) addHostKey(knownHostsFile, hostname string, key ssh.PublicKe
 	}
 	defer file.Close()
 
-	keyType := key.Type()
 	keyData := ssh.MarshalAuthorizedKey(key)
-	entry := fmt.Sprintf("%s %s %s", hostname, &keyType, strings.TrimSpace(string(keyData)))
+	entry := fmt.Sprintf("%s %s", hostname, strings.TrimSpace(string(keyData)))
 
 	_, err = file.WriteString(entry + "\n")
 	return err
@@ -237,9 +321,9 @@ func (c *SSHClient) ExecuteCommand(command string) (string, error) {
 		return "", fmt.Errorf("ssh is error")
 	}
 
-	session, err := c.sshClient.NewSSHClient()
+	session, err := c.sshClient.NewSession()
 	if err != nil {
-		return "", fmt.Errorf("Errorf: %v", err)
+		return "", fmt.Errorf("创建SSH会话失败: %v", err)
 	}
 	defer session.Close()
 
@@ -252,11 +336,24 @@ func (c *SSHClient) ExecuteCommand(command string) (string, error) {
 }
 
 // 上传
+//
+// 远程已存在同名文件时，走 SyncFile 的内容定义分块（CDC）增量传输，
+// 只重传发生变化的部分；远程不存在该文件（全新上传）时，达到
+// parallelStreamThreshold 大小的文件走 UploadFileParallel 多流并发写入，
+// 否则直接整份流式写入。
 func (c *SSHClient) UploadFile(localPath, remotePath string) error {
 	if !c.connected {
 		return fmt.Errorf("SSH is error")
 	}
 
+	if _, err := c.sftpClient.Stat(remotePath); err == nil {
+		return c.SyncFile(c.subsystem, localPath, remotePath)
+	}
+
+	if info, err := os.Stat(localPath); err == nil && info.Size() >= parallelStreamThreshold {
+		return c.UploadFileParallel(localPath, remotePath, defaultParallelStreams)
+	}
+
 	localFile, err := os.Open(localPath)
 	if err != nil {
 		return fmt.Errorf("Errorf: %v", err)
@@ -283,11 +380,18 @@ func (c *SSHClient) UploadFile(localPath, remotePath string) error {
 }
 
 // 下载文件
+//
+// 达到 parallelStreamThreshold 大小的文件走 DownloadFileParallel 多流
+// 并发读取，否则直接整份流式读取。
 func (c *SSHClient) DownloadFile(remotePath, localPath string) error {
 	if !c.connected {
 		return fmt.Errorf("ssh is null")
 	}
 
+	if info, err := c.sftpClient.Stat(remotePath); err == nil && info.Size() >= parallelStreamThreshold {
+		return c.DownloadFileParallel(remotePath, localPath, defaultParallelStreams)
+	}
+
 	remoteFile, err := c.sftpClient.Open(remotePath)
 	if err != nil {
 		return fmt.Errorf("Errorf: %v", err)
@@ -2,13 +2,14 @@ package remote
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"synccli/bindings"
-
-	"github.com/cheggaaa/pb/v3"
+	"synccli/scanner"
 )
 
 // SyncDirection 同步方向
@@ -22,14 +23,28 @@ const (
 
 // SyncOptions 同步选项
 type SyncOptions struct {
-	Direction   SyncDirection `json:"direction"`   // 同步方向
-	DryRun      bool          `json:"dryRun"`      // 是否为试运行
-	Force       bool          `json:"force"`       // 是否强制覆盖
-	Verbose     bool          `json:"verbose"`     // 是否显示详细信息
-	Progress    bool          `json:"progress"`    // 是否显示进度条
-	DeleteExtra bool          `json:"deleteExtra"` // 是否删除多余文件
+	Direction            SyncDirection `json:"direction"`        // 同步方向
+	DryRun               bool          `json:"dryRun"`           // 是否为试运行
+	Force                bool          `json:"force"`            // 是否强制覆盖
+	Verbose              bool          `json:"verbose"`          // 是否显示详细信息
+	Progress             bool          `json:"progress"`         // 是否显示进度条
+	DeleteExtra          bool          `json:"deleteExtra"`      // 是否删除多余文件
+	ResumeRetry          int           `json:"resumeRetry"`      // 断点续传每个分块的最大重试次数
+	BlockSize            int64         `json:"blockSize"`        // 断点续传分块大小（字节）
+	MinResumeSize        int64         `json:"minResumeSize"`    // 触发断点续传的最小文件大小（字节）
+	Concurrency          int           `json:"concurrency"`      // 并发执行同步计划的 worker 数量
+	FailFast             bool          `json:"failFast"`         // 遇到第一个错误时是否立即停止派发
+	UsePythonScanner     bool          `json:"usePythonScanner"` // 是否使用旧的 Python 子进程扫描器
+	EncryptionPassphrase string        `json:"-"`                // config.Encryption 为真时，用于派生流加密密钥的口令，不落盘/不序列化
 }
 
+const (
+	defaultBlockSize      int64 = 10 * 1024 * 1024  // 默认分块大小 10 MiB
+	defaultMinResumeSize  int64 = 100 * 1024 * 1024 // 默认断点续传阈值 100 MiB
+	defaultResumeRetry          = 3
+	maxDefaultConcurrency       = 8
+)
+
 // SyncResult 同步结果
 type SyncResult struct {
 	TotalFiles    int           `json:"totalFiles"`    // 总文件数
@@ -46,13 +61,30 @@ type SyncResult struct {
 // RemoteSyncEngine 远程同步引擎
 type RemoteSyncEngine struct {
 	config       *RemoteConfig
-	sshClient    *SSHClient
+	sshClient    *SSHClient // 仅在 Protocol 为 ssh（默认）时非空
+	sftp         *SFTPSubsystem
+	transport    Transport // 统一的传输后端，scanRemoteFiles/executeSyncPlan 通用路径依赖它
+	backend      Backend   // config.Target 非空时非空，优先于 transport/sshClient（见 Connect）
+	connected    bool
 	options      *SyncOptions
 	pythonClient *bindings.PythonInterface
 }
 
 // NewRemoteSyncEngine 创建新的远程同步引擎
 func NewRemoteSyncEngine(config *RemoteConfig, options *SyncOptions) *RemoteSyncEngine {
+	if options.BlockSize == 0 {
+		options.BlockSize = defaultBlockSize
+	}
+	if options.MinResumeSize == 0 {
+		options.MinResumeSize = defaultMinResumeSize
+	}
+	if options.ResumeRetry == 0 {
+		options.ResumeRetry = defaultResumeRetry
+	}
+	if options.Concurrency == 0 {
+		options.Concurrency = defaultConcurrency()
+	}
+
 	return &RemoteSyncEngine{
 		config:       config,
 		options:      options,
@@ -60,20 +92,82 @@ func NewRemoteSyncEngine(config *RemoteConfig, options *SyncOptions) *RemoteSync
 	}
 }
 
-// Connect 连接到远程服务器
+// Connect 连接到远程服务器。config.Target 非空时优先生效：按 URL
+// scheme 选出一个 Backend（sftp/s3/s3s/webdav/webdavs/file，见
+// NewBackend），用它驱动扫描与传输，这是 synccli 对接 S3 兼容对象存储、
+// WebDAV 共享目录这类没有 Protocol 对应 Transport 实现的后端的入口
+// （S3 尤其如此：Transport 没有 S3 实现）。Target 为空时沿用原先按
+// RemoteConfig.Protocol 选型的路径：ssh（默认）建立 SSH 连接并打开 SFTP
+// 子系统，其余协议（ftp/ftps/webdav/webdavs）通过各自的 Transport 实现
+// 独立连接。
 func (rse *RemoteSyncEngine) Connect() error {
-	rse.sshClient = NewSSHClient(rse.config.SSH)
-	return rse.sshClient.Connect()
+	if rse.config.Target != "" {
+		return rse.connectBackend()
+	}
+
+	if normalizeProtocol(rse.config.Protocol) == ProtocolSSH {
+		rse.sshClient = NewSSHClient(rse.config.SSH)
+		if err := rse.sshClient.Connect(); err != nil {
+			return err
+		}
+		rse.sftp = rse.sshClient.SFTP()
+		rse.transport = newTransport(rse.config, rse.sshClient)
+		rse.connected = true
+		return nil
+	}
+
+	rse.transport = newTransport(rse.config, nil)
+	if err := rse.transport.Connect(); err != nil {
+		return err
+	}
+	rse.connected = true
+	return nil
+}
+
+// connectBackend 是 config.Target 非空时的连接路径：NewBackend 按 Target
+// 的 URL scheme 选出具体实现。sftp scheme 需要一个已连接的 SSHClient，
+// 这里暂不支持（Target 的意义正是覆盖 Protocol 驱动的 SFTP 默认路径，
+// 需要 sftp:// Target 时直接用 Protocol 即可），其余 scheme 都不依赖
+// 已有的 SSH 连接。
+//
+// Backend 本身的构造函数（NewS3Backend/NewWebDAVBackend/NewLocalBackend）
+// 只记录连接参数、不发起网络请求，这与 newTransport 之后还会显式调用
+// Transport.Connect() 探测连通性不同。这里用一次 List("") 主动探测一次，
+// 让凭据错误、网络不可达这类问题在 Connect 阶段就报错，而不是等到
+// SyncDirectory 建目录或扫描时才暴露。
+func (rse *RemoteSyncEngine) connectBackend() error {
+	backend, err := NewBackend(rse.config.Target, rse.config, nil)
+	if err != nil {
+		return fmt.Errorf("连接Target后端失败: %v", err)
+	}
+	if _, err := backend.List(""); err != nil {
+		return fmt.Errorf("连接Target后端失败: %v", err)
+	}
+	rse.backend = backend
+	rse.connected = true
+	return nil
 }
 
 // Disconnect 断开远程连接
 func (rse *RemoteSyncEngine) Disconnect() error {
+	rse.connected = false
 	if rse.sshClient != nil {
 		return rse.sshClient.Close()
 	}
+	if rse.transport != nil {
+		return rse.transport.Close()
+	}
 	return nil
 }
 
+// isConnected 报告当前引擎是否已建立远程连接，不关心具体传输协议。
+func (rse *RemoteSyncEngine) isConnected() bool {
+	if rse.sshClient != nil {
+		return rse.sshClient.IsConnected()
+	}
+	return rse.connected
+}
+
 // SyncDirectory 同步目录
 func (rse *RemoteSyncEngine) SyncDirectory(localPath, remotePath string) (*SyncResult, error) {
 	startTime := time.Now()
@@ -81,8 +175,8 @@ func (rse *RemoteSyncEngine) SyncDirectory(localPath, remotePath string) (*SyncR
 		Errors: make([]string, 0),
 	}
 
-	if !rse.sshClient.IsConnected() {
-		return nil, fmt.Errorf("SSH未连接")
+	if !rse.isConnected() {
+		return nil, fmt.Errorf("远程连接未建立")
 	}
 
 	// 如果是相对路径，转换为绝对路径
@@ -155,8 +249,47 @@ type SyncItem struct {
 	Action     string `json:"action"` // upload, download, delete
 }
 
-// scanLocalFiles 扫描本地文件（使用Python扫描器）
+// scanLocalFiles 扫描本地文件。
+//
+// 默认使用内置的纯 Go 扫描器（synccli/scanner），通过 SyncOptions.UsePythonScanner
+// 可以切回旧的 Python 子进程扫描器以保持向后兼容。
 func (rse *RemoteSyncEngine) scanLocalFiles(localPath string) (map[string]*FileInfo, error) {
+	if rse.options.UsePythonScanner {
+		return rse.scanLocalFilesPython(localPath)
+	}
+	return rse.scanLocalFilesGo(localPath)
+}
+
+// scanLocalFilesGo 使用内置 Go 扫描器扫描本地文件，是 scanner 包结果到
+// RemoteSyncEngine.FileInfo 的薄适配层。
+func (rse *RemoteSyncEngine) scanLocalFilesGo(localPath string) (map[string]*FileInfo, error) {
+	sc := scanner.NewScanner(scanner.ScanOptions{
+		ExcludePatterns: rse.config.ExcludeList,
+		CacheEnabled:    true,
+		CacheFile:       scanCacheFilePath(),
+	})
+
+	scanned, err := sc.ScanDirectory(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("Go扫描器扫描失败: %v", err)
+	}
+
+	files := make(map[string]*FileInfo, len(scanned))
+	for relPath, info := range scanned {
+		files[relPath] = &FileInfo{
+			Path:    info.Path,
+			Size:    info.Size,
+			ModTime: info.ModTime,
+			IsDir:   info.IsDir,
+			Hash:    info.Hash,
+		}
+	}
+
+	return files, nil
+}
+
+// scanLocalFilesPython 扫描本地文件（使用Python扫描器，--use-python-scanner 时启用）
+func (rse *RemoteSyncEngine) scanLocalFilesPython(localPath string) (map[string]*FileInfo, error) {
 	files := make(map[string]*FileInfo)
 
 	// 使用Python扫描器扫描本地文件
@@ -179,14 +312,19 @@ func (rse *RemoteSyncEngine) scanLocalFiles(localPath string) (map[string]*FileI
 		}
 
 		// 解析时间字符串
-		modTime, err := time.Parse("2006-01-02T15:04:05Z", pyFile.ModifiedTime)
+		modTime, err := time.Parse("2006-01-02T15:04:05Z", pyFile.ModifiledTime)
 		if err != nil {
 			modTime = time.Now() // 如果解析失败，使用当前时间
 		}
 
+		size, err := strconv.ParseInt(pyFile.Size, 10, 64)
+		if err != nil {
+			size = 0
+		}
+
 		fileInfo := &FileInfo{
 			Path:    relPath,
-			Size:    pyFile.Size,
+			Size:    size,
 			ModTime: modTime,
 			IsDir:   false,       // Python扫描器只返回文件，不返回目录
 			Hash:    pyFile.Hash, // 使用Python计算的哈希值
@@ -198,46 +336,145 @@ func (rse *RemoteSyncEngine) scanLocalFiles(localPath string) (map[string]*FileI
 	return files, nil
 }
 
+// scanCacheFilePath 返回 Go 扫描器哈希缓存文件的路径。
+func scanCacheFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "synccli_scan_cache.json")
+	}
+	return filepath.Join(homeDir, ".synccli", "scan_cache.json")
+}
+
 // scanRemoteFiles 扫描远程文件
+//
+// config.Target 非空时走 Backend.List 的递归扫描（见 scanRemoteFilesBackend），
+// 这是 S3/WebDAV/本地文件系统这类没有 Protocol 对应 Transport 实现（尤其
+// 是 S3）的目标能被扫描到的唯一路径。否则：ssh（默认）协议下通过 SFTP
+// 子系统的 Walk 递归遍历远程目录，取代此前基于 `find -printf` 解析输出的
+// 实现——后者在 BSD/macOS 上语法不同，且无法正确处理包含制表符或换行符
+// 的路径。其余协议（ftp/ftps/webdav/webdavs）不提供等价的单次遍历调用，
+// 走 Transport 接口上的递归 List 实现。
 func (rse *RemoteSyncEngine) scanRemoteFiles(remotePath string) (map[string]*FileInfo, error) {
+	if rse.backend != nil {
+		return rse.scanRemoteFilesBackend(remotePath)
+	}
+	if rse.sshClient != nil {
+		return rse.scanRemoteFilesSFTP(remotePath)
+	}
+	return rse.scanRemoteFilesTransport(remotePath)
+}
+
+// scanRemoteFilesSFTP 是 ssh 协议下基于 SFTP Walk 的扫描实现。
+func (rse *RemoteSyncEngine) scanRemoteFilesSFTP(remotePath string) (map[string]*FileInfo, error) {
 	files := make(map[string]*FileInfo)
 
-	// 使用SSH命令递归列出文件
-	command := fmt.Sprintf("find '%s' -type f -printf '%%P\\t%%s\\t%%T@\\n' 2>/dev/null || true", remotePath)
-	output, err := rse.sshClient.ExecuteCommand(command)
-	if err != nil {
-		// 如果目录不存在，返回空列表
+	if _, err := rse.sftp.Stat(remotePath); err != nil {
+		// 目录不存在时视为空列表
 		return files, nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	for _, line := range lines {
-		if line == "" {
+	walker := rse.sftp.Walk(remotePath)
+	for walker.Step() {
+		if walker.Err() != nil {
+			continue
+		}
+		if walker.Stat().IsDir() {
+			continue
+		}
+
+		relPath, err := filepath.Rel(remotePath, walker.Path())
+		if err != nil {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if rse.shouldExclude(relPath) {
 			continue
 		}
 
-		parts := strings.Split(line, "\t")
-		if len(parts) != 3 {
+		files[relPath] = &FileInfo{
+			Path:    relPath,
+			Size:    walker.Stat().Size(),
+			ModTime: walker.Stat().ModTime(),
+			IsDir:   false,
+		}
+	}
+
+	return files, nil
+}
+
+// scanRemoteFilesBackend 是 config.Target 非空时基于 Backend.List（经
+// walkBackend 递归）的扫描实现，与 scanRemoteFilesTransport 是同一个
+// 思路，只是面向 Backend 而不是 Transport。
+//
+// 这里不像 scanRemoteFilesSFTP/scanRemoteFilesTransport 那样先用 Stat
+// 探测 remotePath 是否存在、不存在就直接返回空列表：对象存储没有真正的
+// 目录，S3Backend.Mkdir 是空操作（见 backend_s3.go），SyncDirectory 调用
+// ensureRemoteDirectory 之后 remotePath 这个"目录"本身依然不可 Stat，会
+// 导致每次都被误判为空目录，增量同步形同虚设。S3 的 List 对不存在的前缀
+// 返回空列表而非报错；本地文件系统与 WebDAV 的 List 对不存在的目录会报错，
+// 但 SyncDirectory 在扫描前已用 ensureRemoteDirectory 建好该目录。所以
+// 直接交给 walkBackend 处理；真正的连接/权限错误会从 List 里返回，不会被
+// 这里吞掉。
+func (rse *RemoteSyncEngine) scanRemoteFilesBackend(remotePath string) (map[string]*FileInfo, error) {
+	files := make(map[string]*FileInfo)
+
+	entries, err := walkBackend(rse.backend, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		relPath, err := filepath.Rel(remotePath, entry.Path)
+		if err != nil {
 			continue
 		}
+		relPath = filepath.ToSlash(relPath)
 
-		relPath := parts[0]
 		if rse.shouldExclude(relPath) {
 			continue
 		}
 
-		var size int64
-		var modTime time.Time
+		files[relPath] = &FileInfo{
+			Path:    relPath,
+			Size:    entry.Size,
+			ModTime: entry.ModTime,
+			IsDir:   false,
+		}
+	}
+
+	return files, nil
+}
+
+// scanRemoteFilesTransport 是非 ssh 协议下基于 Transport.List 的递归扫描实现。
+func (rse *RemoteSyncEngine) scanRemoteFilesTransport(remotePath string) (map[string]*FileInfo, error) {
+	files := make(map[string]*FileInfo)
+
+	if _, err := rse.transport.Stat(remotePath); err != nil {
+		// 目录不存在时视为空列表
+		return files, nil
+	}
+
+	entries, err := walkTransport(rse.transport, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		relPath, err := filepath.Rel(remotePath, entry.Path)
+		if err != nil {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
 
-		fmt.Sscanf(parts[1], "%d", &size)
-		var timestamp float64
-		fmt.Sscanf(parts[2], "%f", &timestamp)
-		modTime = time.Unix(int64(timestamp), 0)
+		if rse.shouldExclude(relPath) {
+			continue
+		}
 
 		files[relPath] = &FileInfo{
 			Path:    relPath,
-			Size:    size,
-			ModTime: modTime,
+			Size:    entry.Size,
+			ModTime: entry.ModTime,
 			IsDir:   false,
 		}
 	}
@@ -353,74 +590,7 @@ func (rse *RemoteSyncEngine) calculateSyncPlan(localFiles, remoteFiles map[strin
 }
 
 // executeSyncPlan 执行同步计划
-func (rse *RemoteSyncEngine) executeSyncPlan(plan *SyncPlan, result *SyncResult) error {
-	totalItems := len(plan.Upload) + len(plan.Download) + len(plan.Delete)
-
-	var bar *pb.ProgressBar
-	if rse.options.Progress && totalItems > 0 {
-		bar = pb.StartNew(totalItems)
-		defer bar.Finish()
-	}
-
-	// 执行上传
-	for _, item := range plan.Upload {
-		if bar != nil {
-			bar.Increment()
-		}
-
-		if rse.options.Verbose {
-			fmt.Printf("上传: %s -> %s\n", item.LocalPath, item.RemotePath)
-		}
-
-		if err := rse.sshClient.UploadFile(item.LocalPath, item.RemotePath); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("上传失败 %s: %v", item.LocalPath, err))
-			result.ErrorFiles++
-		} else {
-			result.UploadedFiles++
-			result.TotalSize += item.Size
-		}
-	}
-
-	// 执行下载
-	for _, item := range plan.Download {
-		if bar != nil {
-			bar.Increment()
-		}
-
-		if rse.options.Verbose {
-			fmt.Printf("下载: %s -> %s\n", item.RemotePath, item.LocalPath)
-		}
-
-		if err := rse.sshClient.DownloadFile(item.RemotePath, item.LocalPath); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("下载失败 %s: %v", item.RemotePath, err))
-			result.ErrorFiles++
-		} else {
-			result.DownloadFiles++
-			result.TotalSize += item.Size
-		}
-	}
-
-	// 执行删除
-	for _, item := range plan.Delete {
-		if bar != nil {
-			bar.Increment()
-		}
-
-		if rse.options.Verbose {
-			fmt.Printf("删除: %s\n", item.RemotePath)
-		}
-
-		command := fmt.Sprintf("rm -f '%s'", item.RemotePath)
-		if _, err := rse.sshClient.ExecuteCommand(command); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("删除失败 %s: %v", item.RemotePath, err))
-			result.ErrorFiles++
-		} else {
-			result.DeletedFiles++
-		}
-	}
-
-	return nil
-}
+// executeSyncPlan 的并发实现位于 worker_pool.go。
 
 // printSyncPlan 打印同步计划
 func (rse *RemoteSyncEngine) printSyncPlan(plan *SyncPlan) {
@@ -453,7 +623,8 @@ func (rse *RemoteSyncEngine) printSyncPlan(plan *SyncPlan) {
 
 // ensureRemoteDirectory 确保远程目录存在
 func (rse *RemoteSyncEngine) ensureRemoteDirectory(remotePath string) error {
-	command := fmt.Sprintf("mkdir -p '%s'", remotePath)
-	_, err := rse.sshClient.ExecuteCommand(command)
-	return err
+	if rse.backend != nil {
+		return rse.backend.Mkdir(remotePath)
+	}
+	return rse.transport.Mkdir(remotePath)
 }
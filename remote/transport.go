@@ -0,0 +1,300 @@
+package remote
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+// 支持的远程协议标识，对应 RemoteConfig.Protocol。WebDAVS 与 WebDAV 的
+// 关系和 FTPS 与 FTP 的关系一致：同一套连接参数，只是底层换成 TLS
+// （WebDAV 换成 https://，FTP 换成显式 AUTH TLS）。
+const (
+	ProtocolSSH     = "ssh"
+	ProtocolFTP     = "ftp"
+	ProtocolFTPS    = "ftps"
+	ProtocolWebDAV  = "webdav"
+	ProtocolWebDAVS = "webdavs"
+)
+
+// TransportCapabilities 描述一个传输后端具备的能力，同步计划器据此
+// 调整行为，而不是假设所有远程端都是 POSIX-on-SSH。
+type TransportCapabilities struct {
+	SupportsResume   bool // 是否支持断点续传
+	SupportsChmod    bool // 是否支持设置文件权限
+	SupportsSymlinks bool // 是否支持符号链接
+}
+
+// TransportFileInfo 是跨后端统一的远程文件信息。
+type TransportFileInfo struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// Transport 是远程文件传输后端的统一接口，SSH/SFTP、FTP/FTPS、WebDAV
+// 都以此接口接入同步引擎，scanRemoteFiles 与 executeSyncPlan 的通用
+// 路径只依赖该接口，不关心具体协议。
+type Transport interface {
+	Connect() error
+	Close() error
+	List(remotePath string) ([]TransportFileInfo, error)
+	Stat(remotePath string) (TransportFileInfo, error)
+	Put(localPath, remotePath string) error
+	Get(remotePath, localPath string) error
+	Remove(remotePath string) error
+	Mkdir(remotePath string) error
+	Capabilities() TransportCapabilities
+}
+
+// normalizeProtocol 将 Protocol 字段规整为受支持的取值，默认回退到 ssh。
+func normalizeProtocol(protocol string) string {
+	switch protocol {
+	case ProtocolFTP, ProtocolFTPS, ProtocolWebDAV, ProtocolWebDAVS:
+		return protocol
+	default:
+		return ProtocolSSH
+	}
+}
+
+// newTransport 根据 RemoteConfig.Protocol 选择具体的传输后端实现。
+// ssh（默认）复用已建立的 SSHClient 及其 SFTP 子系统；其余协议各自
+// 独立连接。
+func newTransport(config *RemoteConfig, sshClient *SSHClient) Transport {
+	switch normalizeProtocol(config.Protocol) {
+	case ProtocolFTP:
+		return NewFTPTransport(config.SSH, false)
+	case ProtocolFTPS:
+		return NewFTPTransport(config.SSH, true)
+	case ProtocolWebDAV:
+		return NewWebDAVTransport(config.SSH, false)
+	case ProtocolWebDAVS:
+		return NewWebDAVTransport(config.SSH, true)
+	default:
+		return &sshTransportAdapter{client: sshClient}
+	}
+}
+
+// sshTransportAdapter 将已连接的 SSHClient 适配为 Transport 接口，
+// 生命周期（Connect/Close）仍由 RemoteSyncEngine 直接驱动 SSHClient 管理，
+// 这里的 Connect/Close 只是满足接口、不重复建立/关闭连接。
+type sshTransportAdapter struct {
+	client *SSHClient
+}
+
+func (t *sshTransportAdapter) Connect() error { return nil }
+func (t *sshTransportAdapter) Close() error   { return nil }
+
+func (t *sshTransportAdapter) List(remotePath string) ([]TransportFileInfo, error) {
+	entries, err := t.client.SFTP().Client().ReadDir(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程目录失败 %s: %v", remotePath, err)
+	}
+
+	infos := make([]TransportFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, TransportFileInfo{
+			Path:    path.Join(remotePath, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+			IsDir:   entry.IsDir(),
+		})
+	}
+	return infos, nil
+}
+
+func (t *sshTransportAdapter) Stat(remotePath string) (TransportFileInfo, error) {
+	info, err := t.client.SFTP().Stat(remotePath)
+	if err != nil {
+		return TransportFileInfo{}, err
+	}
+	return TransportFileInfo{
+		Path:    remotePath,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}, nil
+}
+
+func (t *sshTransportAdapter) Put(localPath, remotePath string) error {
+	return t.client.SFTP().Upload(localPath, remotePath)
+}
+
+func (t *sshTransportAdapter) Get(remotePath, localPath string) error {
+	return t.client.SFTP().Download(remotePath, localPath)
+}
+
+func (t *sshTransportAdapter) Remove(remotePath string) error {
+	return t.client.SFTP().Remove(remotePath)
+}
+
+func (t *sshTransportAdapter) Mkdir(remotePath string) error {
+	return t.client.SFTP().Mkdir(remotePath)
+}
+
+func (t *sshTransportAdapter) Capabilities() TransportCapabilities {
+	return TransportCapabilities{SupportsResume: true, SupportsChmod: true, SupportsSymlinks: true}
+}
+
+// walkTransport 递归遍历一个 Transport 后端上的远程目录树，用于不支持
+// SFTP Walk 的后端（FTP/WebDAV）。
+func walkTransport(t Transport, root string) ([]TransportFileInfo, error) {
+	var results []TransportFileInfo
+
+	entries, err := t.List(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir {
+			children, err := walkTransport(t, entry.Path)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, children...)
+			continue
+		}
+		results = append(results, entry)
+	}
+
+	return results, nil
+}
+
+// executeSyncPlanGeneric 是面向非 SSH 后端（FTP/WebDAV 等）的顺序执行
+// 路径：这些后端通常不支持每个 worker 独立会话或断点续传，因此退化为
+// 单连接顺序执行，而不是 worker_pool.go 中基于多个 SFTP 会话的并发实现。
+func (rse *RemoteSyncEngine) executeSyncPlanGeneric(plan *SyncPlan, result *SyncResult) error {
+	totalItems := len(plan.Upload) + len(plan.Download) + len(plan.Delete)
+
+	capabilities := rse.transport.Capabilities()
+	if rse.options.Verbose {
+		fmt.Printf("传输后端能力: 断点续传=%v 权限保留=%v 符号链接=%v\n",
+			capabilities.SupportsResume, capabilities.SupportsChmod, capabilities.SupportsSymlinks)
+	}
+	if !capabilities.SupportsResume {
+		for _, item := range plan.Upload {
+			if item.Size >= rse.options.MinResumeSize {
+				fmt.Printf("警告: %s 已达到断点续传阈值，但当前传输后端不支持断点续传，将整份重新传输\n", item.LocalPath)
+			}
+		}
+	}
+
+	var bar *pb.ProgressBar
+	if rse.options.Progress && totalItems > 0 {
+		bar = pb.StartNew(totalItems)
+		defer bar.Finish()
+	}
+
+	for _, item := range plan.Upload {
+		if bar != nil {
+			bar.Increment()
+		}
+		if err := rse.transport.Put(item.LocalPath, item.RemotePath); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("上传失败 %s: %v", item.LocalPath, err))
+			result.ErrorFiles++
+		} else {
+			result.UploadedFiles++
+			result.TotalSize += item.Size
+		}
+	}
+
+	for _, item := range plan.Download {
+		if bar != nil {
+			bar.Increment()
+		}
+		if err := rse.transport.Get(item.RemotePath, item.LocalPath); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("下载失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DownloadFiles++
+			result.TotalSize += item.Size
+		}
+	}
+
+	for _, item := range plan.Delete {
+		if bar != nil {
+			bar.Increment()
+		}
+		if err := rse.transport.Remove(item.RemotePath); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("删除失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DeletedFiles++
+		}
+	}
+
+	return nil
+}
+
+// executeSyncPlanBackend 是 config.Target 非空时的执行路径：Backend 没有
+// SFTP worker 池那样廉价的每-worker 独立会话语义（S3/WebDAV 的底层客户端
+// 也不是为并发单连接设计的），因此和 executeSyncPlanGeneric 一样退化为
+// 单连接顺序执行；加密与否的判断和 runSyncItem 保持一致，只是落到
+// uploadEncrypted/uploadPlain 这对 Backend 通用实现上。
+func (rse *RemoteSyncEngine) executeSyncPlanBackend(plan *SyncPlan, result *SyncResult) error {
+	totalItems := len(plan.Upload) + len(plan.Download) + len(plan.Delete)
+
+	var bar *pb.ProgressBar
+	if rse.options.Progress && totalItems > 0 {
+		bar = pb.StartNew(totalItems)
+		defer bar.Finish()
+	}
+
+	encrypted := rse.config.Encryption && rse.options.EncryptionPassphrase != ""
+
+	for _, item := range plan.Upload {
+		if bar != nil {
+			bar.Increment()
+		}
+		var err error
+		if encrypted {
+			err = rse.uploadEncrypted(rse.backend, item.LocalPath, item.RemotePath)
+		} else {
+			err = rse.uploadPlain(rse.backend, item.LocalPath, item.RemotePath)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("上传失败 %s: %v", item.LocalPath, err))
+			result.ErrorFiles++
+		} else {
+			result.UploadedFiles++
+			result.TotalSize += item.Size
+		}
+	}
+
+	for _, item := range plan.Download {
+		if bar != nil {
+			bar.Increment()
+		}
+		var err error
+		if encrypted {
+			err = rse.downloadEncrypted(rse.backend, item.RemotePath, item.LocalPath)
+		} else {
+			err = rse.downloadPlain(rse.backend, item.RemotePath, item.LocalPath)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("下载失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DownloadFiles++
+			result.TotalSize += item.Size
+		}
+	}
+
+	for _, item := range plan.Delete {
+		if bar != nil {
+			bar.Increment()
+		}
+		if err := rse.backend.Remove(item.RemotePath); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("删除失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DeletedFiles++
+		}
+	}
+
+	return nil
+}
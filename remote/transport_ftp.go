@@ -0,0 +1,248 @@
+package remote
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// ftpPoolSize 是 FTPTransport 维护的并发 FTP 控制连接数：每条 FTP 控制
+// 连接同一时刻只能跑一个数据传输（PASV 打开的数据连接与发起它的控制连接
+// 绑定），单连接会把 List/Put/Get 全部串行化在一条连接上。开一个小连接池
+// 后，并发的调用方可以各用一条连接，类似参考实现里的并发 FTP 上传器；
+// 同步计划本身由 executeSyncPlanGeneric 顺序执行，同一时刻只占用池中的
+// 一条连接。
+const ftpPoolSize = 4
+
+// FTPTransport 是基于 FTP/FTPS 的 Transport 实现，复用 SSHConfig 中的
+// Host/Port/Username/Password/Timeout 字段作为连接参数（FTP 不需要
+// KeyFile/KnownHostsFile，这些字段被忽略）。
+type FTPTransport struct {
+	config      *SSHConfig
+	explicitTLS bool
+
+	mu   sync.Mutex
+	free []*ftp.ServerConn
+}
+
+// NewFTPTransport 创建一个 FTP 传输后端，explicitTLS 为 true 时使用 FTPS
+// （AUTH TLS 显式加密）。
+func NewFTPTransport(config *SSHConfig, explicitTLS bool) *FTPTransport {
+	return &FTPTransport{config: config, explicitTLS: explicitTLS}
+}
+
+// dial 建立并登录一条新的 FTP 控制连接，用于填充连接池。
+func (t *FTPTransport) dial() (*ftp.ServerConn, error) {
+	addr := fmt.Sprintf("%s:%d", t.config.Host, t.config.Port)
+	timeout := time.Duration(t.config.Timeout) * time.Second
+
+	opts := []ftp.DialOption{ftp.DialWithTimeout(timeout)}
+	if t.explicitTLS {
+		opts = append(opts, ftp.DialWithExplicitTLS(&tls.Config{ServerName: t.config.Host}))
+	}
+
+	conn, err := ftp.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("FTP连接失败: %v", err)
+	}
+
+	if err := conn.Login(t.config.Username, t.config.Password); err != nil {
+		conn.Quit()
+		return nil, fmt.Errorf("FTP登录失败: %v", err)
+	}
+
+	return conn, nil
+}
+
+// Connect 预先建立 ftpPoolSize 条控制连接，填满连接池。
+func (t *FTPTransport) Connect() error {
+	conns := make([]*ftp.ServerConn, 0, ftpPoolSize)
+	for i := 0; i < ftpPoolSize; i++ {
+		conn, err := t.dial()
+		if err != nil {
+			for _, c := range conns {
+				c.Quit()
+			}
+			return err
+		}
+		conns = append(conns, conn)
+	}
+
+	t.mu.Lock()
+	t.free = conns
+	t.mu.Unlock()
+	return nil
+}
+
+// acquire 从池中取出一条空闲连接，池为空时临时多开一条；release 会把它和
+// 其余连接一样放回池中，池中的连接数因此可能超过 ftpPoolSize。
+func (t *FTPTransport) acquire() (*ftp.ServerConn, error) {
+	t.mu.Lock()
+	n := len(t.free)
+	if n > 0 {
+		conn := t.free[n-1]
+		t.free = t.free[:n-1]
+		t.mu.Unlock()
+		return conn, nil
+	}
+	t.mu.Unlock()
+
+	return t.dial()
+}
+
+// release 将用完的连接放回池中以便复用。
+func (t *FTPTransport) release(conn *ftp.ServerConn) {
+	t.mu.Lock()
+	t.free = append(t.free, conn)
+	t.mu.Unlock()
+}
+
+func (t *FTPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var lastErr error
+	for _, conn := range t.free {
+		if err := conn.Quit(); err != nil {
+			lastErr = err
+		}
+	}
+	t.free = nil
+	return lastErr
+}
+
+func (t *FTPTransport) List(remotePath string) ([]TransportFileInfo, error) {
+	conn, err := t.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer t.release(conn)
+
+	entries, err := conn.List(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程目录失败 %s: %v", remotePath, err)
+	}
+
+	infos := make([]TransportFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		infos = append(infos, TransportFileInfo{
+			Path:    path.Join(remotePath, entry.Name),
+			Size:    int64(entry.Size),
+			ModTime: entry.Time,
+			IsDir:   entry.Type == ftp.EntryTypeFolder,
+		})
+	}
+	return infos, nil
+}
+
+func (t *FTPTransport) Stat(remotePath string) (TransportFileInfo, error) {
+	dir := path.Dir(remotePath)
+	base := path.Base(remotePath)
+
+	entries, err := t.List(dir)
+	if err != nil {
+		return TransportFileInfo{}, err
+	}
+
+	for _, entry := range entries {
+		if path.Base(entry.Path) == base {
+			return entry, nil
+		}
+	}
+	return TransportFileInfo{}, fmt.Errorf("远程路径不存在: %s", remotePath)
+}
+
+func (t *FTPTransport) Put(localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败: %v", err)
+	}
+	defer localFile.Close()
+
+	conn, err := t.acquire()
+	if err != nil {
+		return err
+	}
+	defer t.release(conn)
+
+	if err := conn.MakeDir(path.Dir(remotePath)); err != nil {
+		// 目录已存在时 MakeDir 会返回错误，这里忽略，交由 Stor 报告真正的失败。
+		_ = err
+	}
+
+	if err := conn.Stor(remotePath, localFile); err != nil {
+		return fmt.Errorf("FTP上传失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *FTPTransport) Get(remotePath, localPath string) error {
+	conn, err := t.acquire()
+	if err != nil {
+		return err
+	}
+	defer t.release(conn)
+
+	resp, err := conn.Retr(remotePath)
+	if err != nil {
+		return fmt.Errorf("FTP下载失败 %s: %v", remotePath, err)
+	}
+	defer resp.Close()
+
+	if err := os.MkdirAll(path.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败: %v", err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败: %v", err)
+	}
+	defer localFile.Close()
+
+	if _, err := io.Copy(localFile, resp); err != nil {
+		return fmt.Errorf("写入本地文件失败: %v", err)
+	}
+	return nil
+}
+
+func (t *FTPTransport) Remove(remotePath string) error {
+	conn, err := t.acquire()
+	if err != nil {
+		return err
+	}
+	defer t.release(conn)
+
+	if err := conn.Delete(remotePath); err != nil {
+		return fmt.Errorf("FTP删除失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *FTPTransport) Mkdir(remotePath string) error {
+	conn, err := t.acquire()
+	if err != nil {
+		return err
+	}
+	defer t.release(conn)
+
+	if _, err := conn.List(remotePath); err == nil {
+		return nil
+	}
+	if err := conn.MakeDir(remotePath); err != nil {
+		return fmt.Errorf("FTP创建目录失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *FTPTransport) Capabilities() TransportCapabilities {
+	return TransportCapabilities{SupportsResume: false, SupportsChmod: false, SupportsSymlinks: false}
+}
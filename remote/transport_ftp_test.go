@@ -0,0 +1,101 @@
+package remote
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// TestFTPTransportAcquireReleaseReusesPooledConn 验证从池里 acquire 出的
+// 连接经 release 放回后，下一次 acquire 能再次取到同一个连接，而不是每次
+// 都当成池空来走。
+func TestFTPTransportAcquireReleaseReusesPooledConn(t *testing.T) {
+	conn := &ftp.ServerConn{}
+	transport := &FTPTransport{free: []*ftp.ServerConn{conn}}
+
+	got, err := transport.acquire()
+	if err != nil {
+		t.Fatalf("acquire失败: %v", err)
+	}
+	if got != conn {
+		t.Fatal("acquire应当取回池中已有的连接")
+	}
+
+	transport.mu.Lock()
+	poolSize := len(transport.free)
+	transport.mu.Unlock()
+	if poolSize != 0 {
+		t.Fatalf("acquire后池应当为空，got %d", poolSize)
+	}
+
+	transport.release(got)
+
+	transport.mu.Lock()
+	poolSize = len(transport.free)
+	transport.mu.Unlock()
+	if poolSize != 1 {
+		t.Fatalf("release后连接应当被放回池中，got池大小%d", poolSize)
+	}
+}
+
+// TestFTPTransportConcurrentAcquireReleaseNoDuplication 在 -race 下验证并发
+// acquire/release 既不会把同一个连接同时派发给两个调用方，也不会丢失连接。
+func TestFTPTransportConcurrentAcquireReleaseNoDuplication(t *testing.T) {
+	const poolSize = ftpPoolSize
+	conns := make([]*ftp.ServerConn, poolSize)
+	for i := range conns {
+		conns[i] = &ftp.ServerConn{}
+	}
+	transport := &FTPTransport{free: append([]*ftp.ServerConn(nil), conns...)}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	inUse := make(map[*ftp.ServerConn]bool)
+
+	// 恰好并发 poolSize 个 goroutine，每个只acquire/release一次：这样同时
+	// 被取走的连接数永远不会超过池的初始大小，测试不会触发acquire()在池
+	// 空时回退到真实网络dial()的路径。
+	for i := 0; i < poolSize; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			conn, err := transport.acquire()
+			if err != nil {
+				t.Errorf("acquire失败: %v", err)
+				return
+			}
+
+			mu.Lock()
+			if inUse[conn] {
+				mu.Unlock()
+				t.Errorf("同一个连接被并发派发给了两个调用方")
+				return
+			}
+			inUse[conn] = true
+			mu.Unlock()
+
+			mu.Lock()
+			delete(inUse, conn)
+			mu.Unlock()
+
+			transport.release(conn)
+		}()
+	}
+	wg.Wait()
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if len(transport.free) != poolSize {
+		t.Fatalf("所有goroutine结束后池大小应当恢复为%d，got %d", poolSize, len(transport.free))
+	}
+}
+
+func TestFTPTransportCapabilities(t *testing.T) {
+	transport := &FTPTransport{}
+	caps := transport.Capabilities()
+	if caps.SupportsResume || caps.SupportsChmod || caps.SupportsSymlinks {
+		t.Fatalf("FTP后端目前不应当声明支持任何这些能力，got %+v", caps)
+	}
+}
@@ -0,0 +1,126 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/studio-b12/gowebdav"
+)
+
+// WebDAVTransport 是基于 WebDAV 的 Transport 实现，同样复用 SSHConfig
+// 的 Host/Port/Username/Password 字段作为连接参数。
+type WebDAVTransport struct {
+	config *SSHConfig
+	secure bool
+	client *gowebdav.Client
+}
+
+// NewWebDAVTransport 创建一个 WebDAV 传输后端，secure 为 true 时使用
+// https:// 连接（对应 ProtocolWebDAVS），否则使用明文 http://。
+func NewWebDAVTransport(config *SSHConfig, secure bool) *WebDAVTransport {
+	return &WebDAVTransport{config: config, secure: secure}
+}
+
+func (t *WebDAVTransport) Connect() error {
+	baseURL := fmt.Sprintf("%s://%s:%d", webdavURLScheme(t.secure), t.config.Host, t.config.Port)
+	t.client = gowebdav.NewClient(baseURL, t.config.Username, t.config.Password)
+	if err := t.client.Connect(); err != nil {
+		return fmt.Errorf("WebDAV连接失败: %v", err)
+	}
+	return nil
+}
+
+func (t *WebDAVTransport) Close() error {
+	return nil
+}
+
+func (t *WebDAVTransport) List(remotePath string) ([]TransportFileInfo, error) {
+	entries, err := t.client.ReadDir(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程目录失败 %s: %v", remotePath, err)
+	}
+
+	infos := make([]TransportFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, TransportFileInfo{
+			Path:    path.Join(remotePath, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+			IsDir:   entry.IsDir(),
+		})
+	}
+	return infos, nil
+}
+
+func (t *WebDAVTransport) Stat(remotePath string) (TransportFileInfo, error) {
+	info, err := t.client.Stat(remotePath)
+	if err != nil {
+		return TransportFileInfo{}, fmt.Errorf("获取远程文件信息失败 %s: %v", remotePath, err)
+	}
+	return TransportFileInfo{
+		Path:    remotePath,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}, nil
+}
+
+func (t *WebDAVTransport) Put(localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败: %v", err)
+	}
+	defer localFile.Close()
+
+	if err := t.client.MkdirAll(path.Dir(remotePath), 0755); err != nil {
+		return fmt.Errorf("创建远程目录失败: %v", err)
+	}
+
+	if err := t.client.WriteStream(remotePath, localFile, 0644); err != nil {
+		return fmt.Errorf("WebDAV上传失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *WebDAVTransport) Get(remotePath, localPath string) error {
+	stream, err := t.client.ReadStream(remotePath)
+	if err != nil {
+		return fmt.Errorf("WebDAV下载失败 %s: %v", remotePath, err)
+	}
+	defer stream.Close()
+
+	if err := os.MkdirAll(path.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("创建本地目录失败: %v", err)
+	}
+
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("创建本地文件失败: %v", err)
+	}
+	defer localFile.Close()
+
+	if _, err := io.Copy(localFile, stream); err != nil {
+		return fmt.Errorf("写入本地文件失败: %v", err)
+	}
+	return nil
+}
+
+func (t *WebDAVTransport) Remove(remotePath string) error {
+	if err := t.client.Remove(remotePath); err != nil {
+		return fmt.Errorf("WebDAV删除失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *WebDAVTransport) Mkdir(remotePath string) error {
+	if err := t.client.MkdirAll(remotePath, 0755); err != nil {
+		return fmt.Errorf("WebDAV创建目录失败 %s: %v", remotePath, err)
+	}
+	return nil
+}
+
+func (t *WebDAVTransport) Capabilities() TransportCapabilities {
+	return TransportCapabilities{SupportsResume: false, SupportsChmod: false, SupportsSymlinks: false}
+}
@@ -0,0 +1,251 @@
+package remote
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchOptions 配置 watch 模式的行为。
+type WatchOptions struct {
+	DebounceMS      int  // 事件去抖窗口（毫秒），默认 500
+	MaxBatchSize    int  // 单批次最多携带的变更数，超过则立即触发同步
+	IgnoreHiddenDot bool // 是否忽略以 "." 开头的文件/目录
+
+	// ConfigName 与 ConfigUpdates 配合使用：当 watch 基于一个已保存的
+	// 远程配置运行时，调用方可以传入 ConfigManager.Subscribe() 返回的
+	// channel，watch 循环会在收到该配置名对应的 ConfigUpdated 事件时，
+	// 把新的 ExcludeList/RemoteBase 热加载进当前会话，无需重启 watch。
+	// ConfigUpdates 为 nil 时（例如本次 watch 未基于保存的配置启动）
+	// 该功能完全不生效。
+	ConfigName    string
+	ConfigUpdates <-chan ConfigEvent
+}
+
+const (
+	defaultDebounceMS   = 500
+	defaultMaxBatchSize = 200
+)
+
+// withDefaults 填充未设置的 WatchOptions 字段。
+func (o WatchOptions) withDefaults() WatchOptions {
+	if o.DebounceMS <= 0 {
+		o.DebounceMS = defaultDebounceMS
+	}
+	if o.MaxBatchSize <= 0 {
+		o.MaxBatchSize = defaultMaxBatchSize
+	}
+	return o
+}
+
+// Watch 执行一次初始全量同步，随后使用 fsnotify 持续监视本地目录树，
+// 将变更事件去抖合并为小批量的增量同步，通过已建立的 SSH/SFTP 连接和
+// 现有的 worker 池推送到远程。新建的子目录会被自动加入监视。
+func (rse *RemoteSyncEngine) Watch(localPath, remotePath string, opts WatchOptions) error {
+	opts = opts.withDefaults()
+
+	relRemote := remotePath
+	baseRelative := !filepath.IsAbs(remotePath)
+	if baseRelative {
+		remotePath = filepath.Join(rse.config.RemoteBase, remotePath)
+	}
+
+	fmt.Println("执行初始全量同步...")
+	if _, err := rse.SyncDirectory(localPath, remotePath); err != nil {
+		return fmt.Errorf("初始全量同步失败: %v", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("创建文件监视器失败: %v", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchRecursive(watcher, localPath, opts); err != nil {
+		return fmt.Errorf("添加监视目录失败: %v", err)
+	}
+
+	fmt.Printf("开始监视 %s，去抖窗口 %dms\n", localPath, opts.DebounceMS)
+
+	var mu sync.Mutex
+	pending := make(map[string]fsnotify.Op)
+	debounceDuration := time.Duration(opts.DebounceMS) * time.Millisecond
+	var debounceTimer *time.Timer
+
+	flush := func() {
+		mu.Lock()
+		if len(pending) == 0 {
+			mu.Unlock()
+			return
+		}
+		batch := pending
+		pending = make(map[string]fsnotify.Op)
+		mu.Unlock()
+
+		target := remotePath
+		if baseRelative {
+			// RemoteBase 可能已被 applyConfigEvent 热加载为新值
+			target = filepath.Join(rse.config.RemoteBase, relRemote)
+		}
+		rse.syncBatch(localPath, target, batch)
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if opts.IgnoreHiddenDot && isHiddenDotPath(localPath, event.Name) {
+				continue
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, statErr := os.Stat(event.Name); statErr == nil && info.IsDir() {
+					_ = addWatchRecursive(watcher, event.Name, opts)
+				}
+			}
+
+			mu.Lock()
+			pending[event.Name] |= event.Op
+			flushNow := len(pending) >= opts.MaxBatchSize
+			mu.Unlock()
+
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			if flushNow {
+				flush()
+			} else {
+				debounceTimer = time.AfterFunc(debounceDuration, flush)
+			}
+
+		case watchErr, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("文件监视器错误: %v\n", watchErr)
+
+		case ev, ok := <-opts.ConfigUpdates:
+			if !ok {
+				opts.ConfigUpdates = nil
+				continue
+			}
+			rse.applyConfigEvent(opts.ConfigName, ev)
+		}
+	}
+}
+
+// applyConfigEvent 消费一条来自 ConfigManager.Subscribe 的事件：只处理
+// 与当前 watch 会话所用 configName 同名、类型为 ConfigUpdated 的事件，
+// 把新的 ExcludeList/RemoteBase 热加载进当前引擎，使正在运行的 watch
+// 不必重启即可生效最新配置。其余事件（不同名、新增、删除）被忽略 ——
+// RemoteBase 变化只影响后续批次里新计算的远程路径，已经派发的同步不受
+// 影响；Watch 的 remotePath 为绝对路径时不受 RemoteBase 影响。
+func (rse *RemoteSyncEngine) applyConfigEvent(configName string, ev ConfigEvent) {
+	if ev.Type != ConfigUpdated || ev.Name != configName || ev.Config == nil {
+		return
+	}
+
+	rse.config.ExcludeList = ev.Config.ExcludeList
+	rse.config.RemoteBase = ev.Config.RemoteBase
+	fmt.Printf("配置 %s 已更新，已热加载最新的 ExcludeList/RemoteBase\n", configName)
+}
+
+// syncBatch 将一批去抖后的 fsnotify 事件翻译为 SyncItem 并通过既有的
+// worker 池执行，尊重 ExcludeList 与 Direction 配置。
+func (rse *RemoteSyncEngine) syncBatch(localPath, remotePath string, batch map[string]fsnotify.Op) {
+	plan := &SyncPlan{}
+
+	for absPath, op := range batch {
+		relPath, err := filepath.Rel(localPath, absPath)
+		if err != nil {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
+		if rse.shouldExclude(relPath) {
+			continue
+		}
+
+		remoteFull := filepath.Join(remotePath, relPath)
+
+		switch {
+		case op&fsnotify.Remove == fsnotify.Remove || op&fsnotify.Rename == fsnotify.Rename:
+			if rse.options.Direction == SyncToRemote || rse.options.Direction == SyncBoth {
+				plan.Delete = append(plan.Delete, SyncItem{
+					RemotePath: remoteFull,
+					Action:     "delete_remote",
+				})
+			}
+
+		case op&fsnotify.Create == fsnotify.Create || op&fsnotify.Write == fsnotify.Write:
+			info, statErr := os.Stat(absPath)
+			if statErr != nil || info.IsDir() {
+				continue
+			}
+			if rse.options.Direction == SyncToRemote || rse.options.Direction == SyncBoth {
+				plan.Upload = append(plan.Upload, SyncItem{
+					LocalPath:  absPath,
+					RemotePath: remoteFull,
+					Size:       info.Size(),
+					Action:     "upload",
+				})
+			}
+		}
+	}
+
+	if len(plan.Upload)+len(plan.Download)+len(plan.Delete) == 0 {
+		return
+	}
+
+	result := &SyncResult{Errors: make([]string, 0)}
+	if err := rse.executeSyncPlan(plan, result); err != nil {
+		fmt.Printf("增量同步失败: %v\n", err)
+		return
+	}
+
+	fmt.Printf("增量同步完成: 上传 %d, 删除 %d, 错误 %d\n",
+		result.UploadedFiles, result.DeletedFiles, result.ErrorFiles)
+}
+
+// addWatchRecursive 递归地将目录树下的所有目录加入 fsnotify 监视，
+// 隐藏目录（以 "." 开头）在 IgnoreHiddenDot 为 true 时会被跳过。
+func addWatchRecursive(watcher *fsnotify.Watcher, root string, opts WatchOptions) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if opts.IgnoreHiddenDot && p != root && isHiddenDotName(d.Name()) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
+
+// isHiddenDotName 判断目录/文件名是否以 "." 开头（不含 "." 和 ".." 本身）。
+func isHiddenDotName(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// isHiddenDotPath 判断路径中是否存在以 "." 开头的目录或文件分量。
+func isHiddenDotPath(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if isHiddenDotName(part) {
+			return true
+		}
+	}
+	return false
+}
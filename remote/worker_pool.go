@@ -0,0 +1,244 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+
+	"github.com/vbauerster/mpb/v8"
+	"github.com/vbauerster/mpb/v8/decor"
+)
+
+// deltaTransferMinSize 是触发 SyncFile 增量传输的最小文件大小：小于这个
+// 尺寸的文件分块本身的开销（多一轮远程分块哈希比对）就已经超过直接整
+// 份重传，走 SyncFile 没有意义。
+const deltaTransferMinSize = 2 * cdcMinChunkSize
+
+// remoteFileExists 判断 remotePath 当前是否已存在，用于决定一次上传是
+// "首次创建"（整份上传最省事）还是"覆盖已有文件"（可以用 SyncFile 的
+// 增量传输复用远程侧已有字节）。
+func (rse *RemoteSyncEngine) remoteFileExists(sftp *SFTPSubsystem, remotePath string) bool {
+	_, err := sftp.Stat(remotePath)
+	return err == nil
+}
+
+// defaultConcurrency 返回 worker 池的默认大小：min(8, NumCPU)。
+func defaultConcurrency() int {
+	if n := runtime.NumCPU(); n < maxDefaultConcurrency {
+		return n
+	}
+	return maxDefaultConcurrency
+}
+
+// executeSyncPlan 将同步计划中的每一项投递到任务通道，由
+// SyncOptions.Concurrency 个 worker 并发消费。每个 worker 持有独立的
+// SFTP 会话（复用同一条 SSH 连接），通过互斥锁安全地汇总 SyncResult。
+//
+// 支持 Ctrl-C 优雅取消：收到中断信号后停止派发新任务、等待进行中的任务
+// 完成，再返回已完成部分的结果；--fail-fast 模式下第一个错误也会触发
+// 同样的停止派发逻辑。
+//
+// 该并发实现依赖 SSH/SFTP 每个 worker 独立会话的能力；非 ssh 协议
+// （ftp/ftps/webdav）退化为 transport.go 中的单连接顺序执行，config.Target
+// 非空（S3/WebDAV/本地文件系统等 Backend）同样退化为顺序执行，见
+// executeSyncPlanBackend。
+func (rse *RemoteSyncEngine) executeSyncPlan(plan *SyncPlan, result *SyncResult) error {
+	if rse.backend != nil {
+		return rse.executeSyncPlanBackend(plan, result)
+	}
+	if rse.sshClient == nil {
+		return rse.executeSyncPlanGeneric(plan, result)
+	}
+
+	items := make([]SyncItem, 0, len(plan.Upload)+len(plan.Download)+len(plan.Delete))
+	items = append(items, plan.Upload...)
+	items = append(items, plan.Download...)
+	items = append(items, plan.Delete...)
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			fmt.Println("\n收到中断信号，正在停止派发新任务并等待进行中的任务完成...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var progress *mpb.Progress
+	var overallBar *mpb.Bar
+	if rse.options.Progress {
+		progress = mpb.New(mpb.WithWidth(60))
+		overallBar = progress.AddBar(int64(len(items)),
+			mpb.PrependDecorators(decor.Name("总进度")),
+			mpb.AppendDecorators(decor.CountersNoUnit("%d / %d")),
+		)
+	}
+
+	taskCh := make(chan SyncItem)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for workerID := 0; workerID < rse.options.Concurrency; workerID++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			rse.runWorker(ctx, cancel, workerID, taskCh, progress, overallBar, result, &mu)
+		}(workerID)
+	}
+
+	go func() {
+		defer close(taskCh)
+		for _, item := range items {
+			select {
+			case <-ctx.Done():
+				return
+			case taskCh <- item:
+			}
+		}
+	}()
+
+	wg.Wait()
+	if progress != nil {
+		progress.Wait()
+	}
+
+	return nil
+}
+
+// runWorker 消费任务通道中的同步项，直到通道关闭或上下文被取消。
+func (rse *RemoteSyncEngine) runWorker(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	workerID int,
+	taskCh <-chan SyncItem,
+	progress *mpb.Progress,
+	overallBar *mpb.Bar,
+	result *SyncResult,
+	mu *sync.Mutex,
+) {
+	workerSFTP, err := rse.sshClient.NewSFTPSession()
+	if err != nil {
+		mu.Lock()
+		result.Errors = append(result.Errors, fmt.Sprintf("worker-%d 创建SFTP会话失败: %v", workerID, err))
+		mu.Unlock()
+		return
+	}
+	defer workerSFTP.Client().Close()
+
+	var workerBar *mpb.Bar
+	if progress != nil {
+		workerBar = progress.AddBar(1,
+			mpb.BarRemoveOnComplete(),
+			mpb.PrependDecorators(decor.Name(fmt.Sprintf("worker-%d", workerID))),
+		)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-taskCh:
+			if !ok {
+				return
+			}
+
+			if rse.options.Verbose {
+				fmt.Printf("worker-%d 处理: %s\n", workerID, item.Action)
+			}
+
+			err := rse.runSyncItem(workerSFTP, item, result, mu)
+
+			if overallBar != nil {
+				overallBar.Increment()
+			}
+			if workerBar != nil {
+				workerBar.SetCurrent(1)
+			}
+
+			if err != nil && rse.options.FailFast {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// runSyncItem 执行单个同步项并将结果合并到共享的 SyncResult 中。
+func (rse *RemoteSyncEngine) runSyncItem(sftp *SFTPSubsystem, item SyncItem, result *SyncResult, mu *sync.Mutex) error {
+	var err error
+
+	encrypted := rse.config.Encryption && rse.options.EncryptionPassphrase != ""
+
+	switch item.Action {
+	case "upload":
+		switch {
+		case encrypted:
+			err = rse.uploadEncrypted(rse.sshClient, item.LocalPath, item.RemotePath)
+		case item.Size >= rse.options.MinResumeSize:
+			err = rse.uploadResumable(sftp, item.LocalPath, item.RemotePath)
+		case item.Size >= parallelStreamThreshold:
+			err = rse.sshClient.UploadFileParallel(item.LocalPath, item.RemotePath, defaultParallelStreams)
+		case item.Size >= deltaTransferMinSize && rse.remoteFileExists(sftp, item.RemotePath):
+			err = rse.sshClient.SyncFile(sftp, item.LocalPath, item.RemotePath)
+		default:
+			err = sftp.Upload(item.LocalPath, item.RemotePath)
+		}
+	case "download":
+		switch {
+		case encrypted:
+			err = rse.downloadEncrypted(rse.sshClient, item.RemotePath, item.LocalPath)
+		case item.Size >= parallelStreamThreshold:
+			err = rse.sshClient.DownloadFileParallel(item.RemotePath, item.LocalPath, defaultParallelStreams)
+		default:
+			err = sftp.Download(item.RemotePath, item.LocalPath)
+		}
+	case "delete_remote":
+		err = sftp.Remove(item.RemotePath)
+	default:
+		err = fmt.Errorf("未知的同步操作: %s", item.Action)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	switch item.Action {
+	case "upload":
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("上传失败 %s: %v", item.LocalPath, err))
+			result.ErrorFiles++
+		} else {
+			result.UploadedFiles++
+			result.TotalSize += item.Size
+		}
+	case "download":
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("下载失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DownloadFiles++
+			result.TotalSize += item.Size
+		}
+	case "delete_remote":
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("删除失败 %s: %v", item.RemotePath, err))
+			result.ErrorFiles++
+		} else {
+			result.DeletedFiles++
+		}
+	}
+
+	return err
+}
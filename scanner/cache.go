@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// cacheEntry 是持久化到磁盘的单条缓存记录。
+type cacheEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Hash    string    `json:"hash"`
+}
+
+// DirCache 是按 (path, size, mtime) 建立索引的哈希缓存，用于让未变化的
+// 文件在后续扫描中跳过重新哈希。每次 Save 都会先将旧缓存文件轮转为
+// `.old`，再写入新内容，避免写入过程中崩溃导致缓存损坏丢失。
+type DirCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// loadDirCache 从磁盘加载缓存文件，文件不存在或解析失败时返回一个空缓存。
+func loadDirCache(cacheFile string) *DirCache {
+	c := &DirCache{entries: make(map[string]cacheEntry)}
+
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return c
+	}
+
+	_ = json.Unmarshal(data, &c.entries)
+	return c
+}
+
+// Lookup 在缓存中查找给定文件，只有 size 与 mtime 都匹配时才命中。
+func (c *DirCache) Lookup(path string, size int64, modTime time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok || entry.Size != size || !entry.ModTime.Equal(modTime) {
+		return "", false
+	}
+	return entry.Hash, true
+}
+
+// Update 写入或刷新一条缓存记录。
+func (c *DirCache) Update(path string, size int64, modTime time.Time, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[path] = cacheEntry{Size: size, ModTime: modTime, Hash: hash}
+}
+
+// Save 将缓存持久化到磁盘：已存在的缓存文件先轮转为 `.old`，再写入新内容。
+func (c *DirCache) Save(cacheFile string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.entries, "", " ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(cacheFile); err == nil {
+		if err := os.Rename(cacheFile, cacheFile+".old"); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(cacheFile, data, 0644)
+}
@@ -0,0 +1,198 @@
+// Package scanner 提供纯 Go 实现的本地目录扫描器，取代此前每个文件
+// 都要 fork 一次 `python3 scanner.py` 子进程（约 50ms 开销）的做法。
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// FileInfo 描述一个被扫描到的文件，字段与 remote.FileInfo 保持一致，
+// 以便调用方做零损耗的适配转换。
+type FileInfo struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+	Hash    string
+}
+
+// ScanOptions 配置一次扫描。
+type ScanOptions struct {
+	ExcludePatterns []string // doublestar 风格的 glob 排除规则
+	CacheEnabled    bool     // 是否启用 (path, size, mtime) 哈希缓存
+	CacheFile       string   // 缓存文件路径，CacheEnabled 为 true 时必填
+}
+
+// Scanner 是可复用的目录扫描器。
+type Scanner struct {
+	opts  ScanOptions
+	cache *DirCache
+}
+
+// NewScanner 创建一个新的扫描器，如果启用了缓存会尝试加载已有缓存文件。
+func NewScanner(opts ScanOptions) *Scanner {
+	s := &Scanner{opts: opts}
+	if opts.CacheEnabled && opts.CacheFile != "" {
+		s.cache = loadDirCache(opts.CacheFile)
+	}
+	return s
+}
+
+const hashBufferSize = 64 * 1024 // 64 KiB 复用缓冲区
+
+// ScanDirectory 递归扫描 root 下的所有文件，返回以根目录相对路径为键的
+// FileInfo 映射。哈希计算通过每 CPU 一个 goroutine 的 worker 池并发完成，
+// 命中缓存（path, size, mtime 均未变化）的文件会跳过重新哈希。
+func (s *Scanner) ScanDirectory(root string) (map[string]*FileInfo, error) {
+	type job struct {
+		relPath string
+		path    string
+		info    os.FileInfo
+	}
+
+	var jobs []job
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return relErr
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if s.shouldExclude(relPath) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return infoErr
+		}
+
+		jobs = append(jobs, job{relPath: relPath, path: p, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*FileInfo, len(jobs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+
+	jobCh := make(chan job)
+	workers := runtime.NumCPU()
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, hashBufferSize)
+
+			for j := range jobCh {
+				hash, hashErr := s.hashFile(j.path, j.info, buf)
+				if hashErr != nil {
+					errOnce.Do(func() { firstErr = hashErr })
+					continue
+				}
+
+				fi := &FileInfo{
+					Path:    j.relPath,
+					Size:    j.info.Size(),
+					ModTime: j.info.ModTime(),
+					IsDir:   false,
+					Hash:    hash,
+				}
+
+				mu.Lock()
+				results[j.relPath] = fi
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		jobCh <- j
+	}
+	close(jobCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Save(s.opts.CacheFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// hashFile 计算文件的 SHA-256，命中缓存时直接复用已有哈希值。
+func (s *Scanner) hashFile(path string, info os.FileInfo, buf []byte) (string, error) {
+	if s.cache != nil {
+		if hash, ok := s.cache.Lookup(path, info.Size(), info.ModTime()); ok {
+			return hash, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyBuffer(h, f, buf); err != nil {
+		return "", err
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	if s.cache != nil {
+		s.cache.Update(path, info.Size(), info.ModTime(), hash)
+	}
+
+	return hash, nil
+}
+
+// shouldExclude 基于 doublestar 风格的 glob 规则判断相对路径是否应被排除。
+func (s *Scanner) shouldExclude(relPath string) bool {
+	base := filepath.Base(relPath)
+	for _, pattern := range s.opts.ExcludePatterns {
+		if matched, _ := doublestar.Match(pattern, relPath); matched {
+			return true
+		}
+		if matched, _ := doublestar.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}